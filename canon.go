@@ -0,0 +1,555 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math/big"
+	"sort"
+)
+
+// canon.go has canonical labeling, automorphism groups, and graph hashing.
+//
+// Canonical labeling is by the individualization-refinement scheme of
+// nauty.  See "Practical Graph Isomorphism, II", Brendan McKay and Adolfo
+// Piperno, J. Symbolic Computation 60 (2014).  The implementation here is
+// a basic one without the node invariants and trace comparisons of nauty.
+// It is intended for graphs of modest size.
+
+// CanonicalPermutation computes a canonical labeling of graph g.
+//
+// The returned permutation p is suitable as an argument for g.Permute.
+// After permuting by p, any two isomorphic graphs will be equal.  That is,
+// for isomorphic graphs g and h with canonical permutations pg and ph,
+// g.Permute(pg) and h.Permute(ph) will leave g.Equal(h) true.  Graphs that
+// are not isomorphic will never compare equal after canonical permutation.
+//
+// The method works for directed and undirected graphs and for multigraphs.
+//
+// See also WLHash, which is much faster and can be useful for filtering out
+// non-isomorphic graphs before computing canonical permutations.
+func (g AdjacencyList) CanonicalPermutation() []int {
+	c := newCanon(g)
+	c.search()
+	p := make([]int, len(g))
+	for i, n := range c.best {
+		p[n] = i
+	}
+	return p
+}
+
+// Automorphisms computes the automorphism group of graph g.
+//
+// An automorphism is a permutation of the nodes of g that maps g to itself.
+// Each automorphism is represented as a slice a where node n maps to node
+// a[n].  Returned generators is a list of automorphisms that generate the
+// automorphism group.  The list is not necessarily minimal.  If g has no
+// automorphisms other than the identity, generators will be empty.
+//
+// Also returned is the orbit partition of the nodes of g.  Nodes are in the
+// same orbit if some automorphism maps one to the other.  For each node n,
+// orbits[n] is the least node in the orbit of n.
+//
+// The method works for directed and undirected graphs and for multigraphs.
+//
+// See GroupOrder for computing the order of the automorphism group from the
+// generators.
+func (g AdjacencyList) Automorphisms() (generators [][]NI, orbits []NI) {
+	c := newCanon(g)
+	c.search()
+	ds := NewUnionFind(len(g))
+	for _, a := range c.gens {
+		for n, m := range a {
+			ds.Union(NI(n), m)
+		}
+	}
+	orbits = make([]NI, len(g))
+	least := make([]NI, len(g))
+	for n := range least {
+		least[n] = -1
+	}
+	for n := range orbits {
+		r := ds.Find(NI(n))
+		if least[r] < 0 {
+			least[r] = NI(n)
+		}
+		orbits[n] = least[r]
+	}
+	return c.gens, orbits
+}
+
+// GroupOrder computes the order of a permutation group.
+//
+// The group is given by a list of generators, each a permutation of the
+// integers 0 through n-1 for some n, as returned for example by
+// Automorphisms.  All generators must have the same length.  An empty list
+// of generators represents the trivial group, of order 1.
+//
+// The algorithm is the Schreier-Sims algorithm as presented in "Efficient
+// representation of perm groups", Donald Knuth, Combinatorica 11 (1991).
+func GroupOrder(generators [][]NI) *big.Int {
+	o := big.NewInt(1)
+	if len(generators) == 0 {
+		return o
+	}
+	ss := newSchreierSims(len(generators[0]))
+	for _, g := range generators {
+		ss.add(0, g)
+	}
+	for _, u := range ss.u {
+		o.Mul(o, big.NewInt(int64(len(u))))
+	}
+	return o
+}
+
+// schreierSims holds a stabilizer chain with base 0, 1, ... n-1.
+//
+// Level k represents the subgroup fixing 0 through k-1.  At level k, s[k]
+// holds generators and u[k] holds a transversal, a permutation mapping k
+// to j for each j in the orbit of k.  ui[k] holds the inverses.
+type schreierSims struct {
+	n     int
+	s     [][][]NI
+	u, ui []map[NI][]NI
+}
+
+func newSchreierSims(n int) *schreierSims {
+	ss := &schreierSims{
+		n:  n,
+		s:  make([][][]NI, n),
+		u:  make([]map[NI][]NI, n),
+		ui: make([]map[NI][]NI, n),
+	}
+	id := make([]NI, n)
+	for i := range id {
+		id[i] = NI(i)
+	}
+	for k := range ss.u {
+		ss.u[k] = map[NI][]NI{NI(k): id}
+		ss.ui[k] = map[NI][]NI{NI(k): id}
+	}
+	return ss
+}
+
+// compose returns the permutation p after q.
+func compose(p, q []NI) []NI {
+	r := make([]NI, len(q))
+	for i, x := range q {
+		r[i] = p[x]
+	}
+	return r
+}
+
+// member tests if p, which fixes 0 through k-1, is in the group at level k.
+func (ss *schreierSims) member(k int, p []NI) bool {
+	for ; k < ss.n; k++ {
+		ui, ok := ss.ui[k][p[k]]
+		if !ok {
+			return false
+		}
+		p = compose(ui, p)
+	}
+	return true
+}
+
+// add adds permutation p, which fixes 0 through k-1, to level k.
+func (ss *schreierSims) add(k int, p []NI) {
+	if k == ss.n || ss.member(k, p) {
+		return
+	}
+	ss.s[k] = append(ss.s[k], p)
+	reps := make([][]NI, 0, len(ss.u[k]))
+	for _, r := range ss.u[k] {
+		reps = append(reps, r)
+	}
+	for _, r := range reps {
+		ss.close(k, compose(p, r))
+	}
+}
+
+// close extends the orbit at level k with permutation t, or if the image
+// of k is already in the orbit, sifts the resulting Schreier generator to
+// level k+1.
+func (ss *schreierSims) close(k int, t []NI) {
+	j := t[k]
+	if ui, ok := ss.ui[k][j]; ok {
+		ss.add(k+1, compose(ui, t))
+		return
+	}
+	ss.u[k][j] = t
+	ti := make([]NI, len(t))
+	for i, x := range t {
+		ti[x] = NI(i)
+	}
+	ss.ui[k][j] = ti
+	for _, p := range ss.s[k] {
+		ss.close(k, compose(p, t))
+	}
+}
+
+// WLHash computes a Weisfeiler-Lehman hash of graph g.
+//
+// Nodes are colored by iterated color refinement, where at each iteration
+// a node's color is hashed with the colors of its to and from neighbors.
+// The result is a hash of the final multiset of colors.
+//
+// Argument iterations limits the number of refinement iterations.  If it is
+// <= 0, refinement is iterated until node colors are stable.
+//
+// Isomorphic graphs will always have the same hash.  Graphs with different
+// hashes are certainly not isomorphic.  Graphs with the same hash may or may
+// not be isomorphic.  Use a method such as Isomorphism or
+// CanonicalPermutation to resolve them.
+func (g AdjacencyList) WLHash(iterations int) uint64 {
+	in := make([]int, len(g))
+	for _, to := range g {
+		for _, to := range to {
+			in[to]++
+		}
+	}
+	col := make([]uint64, len(g))
+	for n, to := range g {
+		col[n] = wlHash(uint64(len(to)), uint64(in[n]))
+	}
+	tr, _ := Directed{g}.Transpose()
+	next := make([]uint64, len(g))
+	var nbs []uint64
+	nColors := wlCount(col)
+	if iterations <= 0 {
+		iterations = len(g)
+	}
+	for i := 0; i < iterations; i++ {
+		for n := range g {
+			nbs = nbs[:0]
+			for _, to := range g[n] {
+				nbs = append(nbs, col[to])
+			}
+			sortUint64(nbs)
+			h := wlHash(append([]uint64{col[n], uint64(len(nbs))}, nbs...)...)
+			nbs = nbs[:0]
+			for _, fr := range tr.AdjacencyList[n] {
+				nbs = append(nbs, col[fr])
+			}
+			sortUint64(nbs)
+			next[n] = wlHash(append([]uint64{h, uint64(len(nbs))}, nbs...)...)
+		}
+		col, next = next, col
+		c := wlCount(col)
+		if c == nColors {
+			break
+		}
+		nColors = c
+	}
+	s := append([]uint64{}, col...)
+	sortUint64(s)
+	return wlHash(append([]uint64{uint64(len(g))}, s...)...)
+}
+
+func wlHash(x ...uint64) uint64 {
+	h := fnv.New64a()
+	var b [8]byte
+	for _, x := range x {
+		binary.LittleEndian.PutUint64(b[:], x)
+		h.Write(b[:])
+	}
+	return h.Sum64()
+}
+
+func wlCount(col []uint64) int {
+	m := map[uint64]struct{}{}
+	for _, c := range col {
+		m[c] = struct{}{}
+	}
+	return len(m)
+}
+
+func sortUint64(s []uint64) {
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+}
+
+// canon holds state for a canonical labeling search.
+type canon struct {
+	out, in   AdjacencyList
+	gens      [][]NI // automorphisms found
+	path      []NI   // individualized nodes of the current search tree node
+	first     []NI   // node ordering at the first leaf
+	firstCert []NI
+	firstPath []NI
+	best      []NI // node ordering at the best leaf
+	bestCert  []NI
+	bestPath  []NI
+}
+
+// cPartition is an ordered partition of nodes.
+//
+// Lab lists all nodes, cell by cell.  Cell gives, for each node, the
+// position in lab where its cell starts.
+type cPartition struct {
+	lab  []NI
+	cell []int
+}
+
+func newCanon(g AdjacencyList) *canon {
+	tr, _ := Directed{g}.Transpose()
+	return &canon{out: g, in: tr.AdjacencyList}
+}
+
+// search runs the full search from the unit partition.
+func (c *canon) search() {
+	n := len(c.out)
+	p := cPartition{make([]NI, n), make([]int, n)}
+	for i := range p.lab {
+		p.lab[i] = NI(i)
+	}
+	c.searchNode(p, 0)
+}
+
+// cellEnd returns the end position of the cell starting at s.
+func (p cPartition) cellEnd(s int) int {
+	e := s + 1
+	for e < len(p.lab) && p.cell[p.lab[e]] == s {
+		e++
+	}
+	return e
+}
+
+// refine refines partition p to an equitable partition.
+//
+// Nodes within each cell are distinguished by the cells of their to and
+// from neighbors, and new cells ordered by these neighbor cell lists.  This
+// depends only on the cell structure and not on node numbers so that
+// refinement is isomorphism invariant.
+func (c *canon) refine(p cPartition) cPartition {
+	n := len(p.lab)
+	lab := append([]NI{}, p.lab...)
+	cell := append([]int{}, p.cell...)
+	sig := make([][]int, n)
+	nCells := 0
+	for i := range lab {
+		if cell[lab[i]] == i {
+			nCells++
+		}
+	}
+	for {
+		for v := range sig {
+			s := sig[v][:0]
+			s = append(s, len(c.out[v]))
+			for _, to := range c.out[v] {
+				s = append(s, cell[to])
+			}
+			sort.Ints(s[1:])
+			x := len(s)
+			for _, fr := range c.in[v] {
+				s = append(s, cell[fr])
+			}
+			sort.Ints(s[x:])
+			sig[v] = s
+		}
+		sort.Slice(lab, func(i, j int) bool {
+			v, w := lab[i], lab[j]
+			if cell[v] != cell[w] {
+				return cell[v] < cell[w]
+			}
+			return compareInts(sig[v], sig[w]) < 0
+		})
+		next := make([]int, n)
+		nNext := 0
+		for i, v := range lab {
+			if i == 0 || cell[v] != cell[lab[i-1]] ||
+				compareInts(sig[v], sig[lab[i-1]]) != 0 {
+				next[v] = i
+				nNext++
+			} else {
+				next[v] = next[lab[i-1]]
+			}
+		}
+		cell = next
+		if nNext == nCells {
+			return cPartition{lab, cell}
+		}
+		nCells = nNext
+	}
+}
+
+// individualize splits node v from its cell, placing it first.
+func (p cPartition) individualize(v NI) cPartition {
+	lab := append([]NI{}, p.lab...)
+	cell := append([]int{}, p.cell...)
+	s := cell[v]
+	e := p.cellEnd(s)
+	for i := s; i < e; i++ {
+		if lab[i] == v {
+			lab[i], lab[s] = lab[s], lab[i]
+			break
+		}
+		cell[lab[i]] = s + 1
+	}
+	for i := s + 1; i < e; i++ {
+		cell[lab[i]] = s + 1
+	}
+	return cPartition{lab, cell}
+}
+
+// searchNode searches the subtree at the search tree node with partition p
+// at the given depth.
+//
+// The return value is the depth of the search tree node where the search
+// should continue.  Normally this is the parent, depth-1, but when an
+// automorphism is found it can allow skipping the rest of a subtree.
+func (c *canon) searchNode(p cPartition, depth int) int {
+	p = c.refine(p)
+	// target cell is first non-singleton cell
+	t := -1
+	for i := 0; i < len(p.lab); {
+		e := p.cellEnd(i)
+		if e-i > 1 {
+			t = i
+			break
+		}
+		i = e
+	}
+	if t < 0 {
+		return c.leaf(p.lab, depth)
+	}
+	cellNodes := append([]NI{}, p.lab[t:p.cellEnd(t)]...)
+	var tried []NI
+	for _, v := range cellNodes {
+		if c.equivalent(v, tried, depth) {
+			continue
+		}
+		c.path = append(c.path[:depth], v)
+		if r := c.searchNode(p.individualize(v), depth+1); r < depth {
+			return r
+		}
+		tried = append(tried, v)
+	}
+	return depth - 1
+}
+
+// equivalent tests if node v is in the same orbit as any node in tried,
+// under the automorphisms found so far that fix the current path.
+func (c *canon) equivalent(v NI, tried []NI, depth int) bool {
+	if len(tried) == 0 {
+		return false
+	}
+	var gens [][]NI
+gen:
+	for _, g := range c.gens {
+		for _, n := range c.path[:depth] {
+			if g[n] != n {
+				continue gen
+			}
+		}
+		gens = append(gens, g)
+	}
+	if len(gens) == 0 {
+		return false
+	}
+	ds := NewUnionFind(len(c.out))
+	for _, g := range gens {
+		for n, m := range g {
+			ds.Union(NI(n), m)
+		}
+	}
+	r := ds.Find(v)
+	for _, u := range tried {
+		if ds.Find(u) == r {
+			return true
+		}
+	}
+	return false
+}
+
+// leaf processes a leaf of the search tree, with the discrete partition
+// given as node ordering lab.
+func (c *canon) leaf(lab []NI, depth int) int {
+	cert := c.certificate(lab)
+	if c.first == nil {
+		c.first = lab
+		c.firstCert = cert
+		c.firstPath = append([]NI{}, c.path[:depth]...)
+		c.best, c.bestCert, c.bestPath = c.first, c.firstCert, c.firstPath
+		return depth - 1
+	}
+	if compareNIs(cert, c.firstCert) == 0 {
+		return c.automorphism(c.first, lab, c.firstPath)
+	}
+	switch compareNIs(cert, c.bestCert) {
+	case -1:
+		c.best = lab
+		c.bestCert = cert
+		c.bestPath = append([]NI{}, c.path[:depth]...)
+	case 0:
+		return c.automorphism(c.best, lab, c.bestPath)
+	}
+	return depth - 1
+}
+
+// automorphism records the automorphism mapping leaf ordering l1 to l2.
+//
+// Returned is the depth at which path diverges from path p1 of l1.
+// The subtree below that depth is equivalent to one already searched.
+func (c *canon) automorphism(l1, l2, p1 []NI) int {
+	a := make([]NI, len(l1))
+	for i, n := range l1 {
+		a[n] = l2[i]
+	}
+	c.gens = append(c.gens, a)
+	k := 0
+	for k < len(p1) && k < len(c.path) && p1[k] == c.path[k] {
+		k++
+	}
+	return k
+}
+
+// certificate returns the graph relabeled by node ordering lab, encoded as
+// a list of arc lists each preceded by its length.
+func (c *canon) certificate(lab []NI) []NI {
+	pos := make([]NI, len(lab))
+	for i, n := range lab {
+		pos[n] = NI(i)
+	}
+	var cert []NI
+	for _, n := range lab {
+		cert = append(cert, NI(len(c.out[n])))
+		x := len(cert)
+		for _, to := range c.out[n] {
+			cert = append(cert, pos[to])
+		}
+		s := cert[x:]
+		sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	}
+	return cert
+}
+
+func compareInts(a, b []int) int {
+	for i, x := range a {
+		switch {
+		case i == len(b) || x > b[i]:
+			return 1
+		case x < b[i]:
+			return -1
+		}
+	}
+	if len(a) < len(b) {
+		return -1
+	}
+	return 0
+}
+
+func compareNIs(a, b []NI) int {
+	for i, x := range a {
+		switch {
+		case i == len(b) || x > b[i]:
+			return 1
+		case x < b[i]:
+			return -1
+		}
+	}
+	if len(a) < len(b) {
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,215 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyList_Automorphisms() {
+	// 0--1--2--3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	gens, orbits := g.Automorphisms()
+	fmt.Println(gens)
+	fmt.Println(orbits)
+	// Output:
+	// [[3 2 1 0]]
+	// [0 1 1 0]
+}
+
+func ExampleAdjacencyList_CanonicalPermutation() {
+	// g:         h:
+	// 0--1--2    2--0--1
+	//    |          |
+	//    3          3
+	var g, h graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	h.AddEdge(2, 0)
+	h.AddEdge(0, 1)
+	h.AddEdge(0, 3)
+	g.Permute(g.CanonicalPermutation())
+	h.Permute(h.CanonicalPermutation())
+	fmt.Println(g.Equal(h.AdjacencyList))
+	for fr, to := range g.AdjacencyList {
+		fmt.Println(fr, to)
+	}
+	// Output:
+	// true
+	// 0 [3]
+	// 1 [3]
+	// 2 [3]
+	// 3 [0 1 2]
+}
+
+func ExampleAdjacencyList_WLHash() {
+	// g:         h:           k:
+	// 0--1--2    2--0--1      0--1--2--3
+	//    |          |
+	//    3          3
+	var g, h, k graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	h.AddEdge(2, 0)
+	h.AddEdge(0, 1)
+	h.AddEdge(0, 3)
+	k.AddEdge(0, 1)
+	k.AddEdge(1, 2)
+	k.AddEdge(2, 3)
+	fmt.Println(g.WLHash(0) == h.WLHash(0))
+	fmt.Println(g.WLHash(0) == k.WLHash(0))
+	// Output:
+	// true
+	// false
+}
+
+func ExampleGroupOrder() {
+	// 3-cube
+	var g graph.Undirected
+	for i := 0; i < 8; i++ {
+		for b := uint(0); b < 3; b++ {
+			if j := i ^ 1<<b; j > i {
+				g.AddEdge(graph.NI(i), graph.NI(j))
+			}
+		}
+	}
+	gens, _ := g.Automorphisms()
+	fmt.Println(graph.GroupOrder(gens))
+	// Output:
+	// 48
+}
+
+func TestAutomorphisms(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 50; i++ {
+		g := graph.GnmUndirected(7, 9, r).AdjacencyList
+		gens, orbits := g.Automorphisms()
+		for _, a := range gens {
+			p := make([]int, len(a))
+			for n, m := range a {
+				p[n] = int(m)
+			}
+			h, _ := g.Copy()
+			h.Permute(p)
+			if !h.Equal(g) {
+				t.Fatal("generator not an automorphism")
+			}
+		}
+		// brute force count of automorphisms and orbits
+		var n int64
+		want := make([]graph.NI, len(g))
+		for i := range want {
+			want[i] = graph.NI(i)
+		}
+		var perm func([]int, int)
+		perm = func(p []int, k int) {
+			if k == len(p) {
+				h, _ := g.Copy()
+				h.Permute(p)
+				if !h.Equal(g) {
+					return
+				}
+				n++
+				for i, j := range p {
+					if graph.NI(i) < want[j] {
+						want[j] = graph.NI(i)
+					}
+				}
+				return
+			}
+			for i := k; i < len(p); i++ {
+				p[k], p[i] = p[i], p[k]
+				perm(p, k+1)
+				p[k], p[i] = p[i], p[k]
+			}
+		}
+		perm(r.Perm(len(g)), 0)
+		if o := graph.GroupOrder(gens); o.Int64() != n {
+			t.Fatalf("GroupOrder = %v, want %d", o, n)
+		}
+		for i := range want {
+			if orbits[i] != want[i] {
+				t.Fatalf("orbits = %v, want %v", orbits, want)
+			}
+		}
+	}
+}
+
+func TestCanonicalPermutation(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	canon := func(g graph.AdjacencyList) graph.AdjacencyList {
+		c, _ := g.Copy()
+		c.Permute(c.CanonicalPermutation())
+		return c
+	}
+	for i := 0; i < 100; i++ {
+		var g graph.AdjacencyList
+		if i%2 == 0 {
+			g = graph.GnmDirected(12, 20, r).AdjacencyList
+		} else {
+			g = graph.GnmUndirected(12, 15, r).AdjacencyList
+		}
+		cg := canon(g)
+		h, _ := g.Copy()
+		h.Permute(r.Perm(len(h)))
+		if g.WLHash(0) != h.WLHash(0) {
+			t.Fatal("WLHash differs for isomorphic graphs")
+		}
+		if !canon(h).Equal(cg) {
+			t.Fatal("canonical forms differ for isomorphic graphs")
+		}
+		// add an arc and graphs are no longer isomorphic
+		h[0] = append(h[0], 1)
+		if canon(h).Equal(cg) {
+			t.Fatal("canonical forms equal for non-isomorphic graphs")
+		}
+	}
+}
+
+func TestCanonicalPermutationSymmetric(t *testing.T) {
+	// Petersen graph, 3-cube, and complete graph.  Highly symmetric graphs
+	// exercise automorphism pruning.
+	var p, q, k graph.Undirected
+	for i := 0; i < 5; i++ {
+		p.AddEdge(graph.NI(i), graph.NI((i+1)%5))
+		p.AddEdge(graph.NI(i), graph.NI(i+5))
+		p.AddEdge(graph.NI(i+5), graph.NI((i+2)%5+5))
+	}
+	for i := 0; i < 8; i++ {
+		for b := uint(0); b < 3; b++ {
+			if j := i ^ 1<<b; j > i {
+				q.AddEdge(graph.NI(i), graph.NI(j))
+			}
+		}
+	}
+	for i := 0; i < 9; i++ {
+		for j := i + 1; j < 9; j++ {
+			k.AddEdge(graph.NI(i), graph.NI(j))
+		}
+	}
+	r := rand.New(rand.NewSource(11))
+	for i, g := range []graph.Undirected{p, q, k} {
+		gens, _ := g.Automorphisms()
+		if o := graph.GroupOrder(gens); o.Int64() != []int64{120, 48, 362880}[i] {
+			t.Fatal("wrong automorphism group order", o)
+		}
+		c, _ := g.Copy()
+		c.Permute(c.CanonicalPermutation())
+		h, _ := g.Copy()
+		h.Permute(r.Perm(h.Order()))
+		h.Permute(h.CanonicalPermutation())
+		if !h.Equal(c.AdjacencyList) {
+			t.Fatal("canonical forms differ for isomorphic graphs")
+		}
+	}
+}
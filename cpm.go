@@ -0,0 +1,145 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// cpm.go has the critical path method for project scheduling.
+
+// CPM holds the result of a critical path method analysis.
+//
+// The graph is an activity-on-arc project network.  Arcs represent
+// activities with durations given by a WeightFunc.  Nodes represent events,
+// the completion of all activities on arcs leading to the node.
+//
+// Node times are event times.  Arc times are activity start times.
+// Arc values are indexed in parallel with the arcs of the graph, so that
+// ArcEarly[fr][x] is the earliest start of activity G[fr][x], for example.
+type CPM struct {
+	G        LabeledDirected
+	W        WeightFunc
+	Duration float64     // project duration, the length of critical paths
+	Early    []float64   // earliest event time of each node
+	Late     []float64   // latest event time of each node
+	Slack    []float64   // Late - Early
+	ArcEarly [][]float64 // earliest start of each activity
+	ArcLate  [][]float64 // latest start of each activity
+	ArcSlack [][]float64 // ArcLate - ArcEarly
+}
+
+// CPM performs a critical path method analysis of a project network.
+//
+// Receiver g must be a directed acyclic graph representing activities on
+// arcs.  WeightFunc w gives activity durations.  See type CPM.
+//
+// Nodes without in-arcs are project start events with earliest time 0.
+// Nodes without out-arcs are project end events with latest time equal to
+// the project duration.
+//
+// If g is cyclic, a non-nil error is returned.
+//
+// Slack is computed in floating point.  With non-integer durations,
+// rounding may leave critical activities with a small non-zero slack.
+func (g LabeledDirected) CPM(w WeightFunc) (*CPM, error) {
+	a := g.LabeledAdjacencyList
+	ordering, cycle := g.Topological()
+	if cycle != nil {
+		return nil, errors.New("not a DAG")
+	}
+	c := &CPM{
+		G:        g,
+		W:        w,
+		Early:    make([]float64, len(a)),
+		Late:     make([]float64, len(a)),
+		Slack:    make([]float64, len(a)),
+		ArcEarly: make([][]float64, len(a)),
+		ArcLate:  make([][]float64, len(a)),
+		ArcSlack: make([][]float64, len(a)),
+	}
+	// forward pass
+	for _, fr := range ordering {
+		e := c.Early[fr]
+		for _, to := range a[fr] {
+			if t := e + w(to.Label); t > c.Early[to.To] {
+				c.Early[to.To] = t
+			}
+		}
+		if e > c.Duration {
+			c.Duration = e
+		}
+	}
+	// backward pass
+	for i := len(ordering) - 1; i >= 0; i-- {
+		fr := ordering[i]
+		l := math.Inf(1)
+		if len(a[fr]) == 0 {
+			l = c.Duration
+		}
+		as := make([]float64, len(a[fr]))
+		al := make([]float64, len(a[fr]))
+		ak := make([]float64, len(a[fr]))
+		for x, to := range a[fr] {
+			as[x] = c.Early[fr]
+			al[x] = c.Late[to.To] - w(to.Label)
+			ak[x] = al[x] - as[x]
+			if al[x] < l {
+				l = al[x]
+			}
+		}
+		c.Late[fr] = l
+		c.Slack[fr] = l - c.Early[fr]
+		c.ArcEarly[fr] = as
+		c.ArcLate[fr] = al
+		c.ArcSlack[fr] = ak
+	}
+	return c, nil
+}
+
+// CriticalPaths emits all critical paths of a CPM analysis.
+//
+// A critical path is a path of activities with zero slack from a project
+// start event to a project end event.  The length of each critical path is
+// the project duration.
+//
+// The emit function is called for each critical path.  The backing slice
+// of the path is reused across emit calls.  If you need to retain the path
+// you must copy it.  Emit must return true to continue or false to stop.
+func (c *CPM) CriticalPaths(emit func(LabeledPath) bool) {
+	a := c.G.LabeledAdjacencyList
+	hasIn := make([]bool, len(a))
+	for _, to := range a {
+		for _, to := range to {
+			hasIn[to.To] = true
+		}
+	}
+	var p []Half
+	var df func(NI, NI) bool
+	df = func(start, n NI) bool {
+		end := true
+		for x, to := range a[n] {
+			if c.ArcSlack[n][x] != 0 {
+				continue
+			}
+			end = false
+			p = append(p, to)
+			ok := df(start, to.To)
+			p = p[:len(p)-1]
+			if !ok {
+				return false
+			}
+		}
+		if end && c.Early[n] == c.Duration {
+			return emit(LabeledPath{start, p})
+		}
+		return true
+	}
+	for n := range a {
+		if !hasIn[n] && c.Slack[n] == 0 && !df(NI(n), NI(n)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledDirected_CPM() {
+	// activities on arcs, labels are durations
+	//
+	//       (3)      (2)
+	//    0 -----> 1 -----> 3
+	//    |        |        ^
+	//    |(2)     |(1)     |(4)
+	//    v        v        |
+	//    2 -----> 4 -------'
+	//       (1)
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 3}, {To: 2, Label: 2}},
+		1: {{To: 3, Label: 2}, {To: 4, Label: 1}},
+		2: {{To: 4, Label: 1}},
+		3: {},
+		4: {{To: 3, Label: 4}},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	c, err := g.CPM(w)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println("duration:", c.Duration)
+	fmt.Println("early:   ", c.Early)
+	fmt.Println("late:    ", c.Late)
+	fmt.Println("slack:   ", c.Slack)
+	fmt.Println("arc slack:", c.ArcSlack)
+	c.CriticalPaths(func(p graph.LabeledPath) bool {
+		fmt.Println("critical:", p)
+		return true
+	})
+	// Output:
+	// duration: 8
+	// early:    [0 3 2 8 4]
+	// late:     [0 3 3 8 4]
+	// slack:    [0 0 1 0 0]
+	// arc slack: [[0 1] [3 0] [1] [] [0]]
+	// critical: {0 [{1 3} {4 1} {3 4}]}
+}
+
+func TestCPM(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(8)
+		// random DAG: arcs only from lower to higher node numbers
+		a := make(graph.LabeledAdjacencyList, n)
+		for fr := 0; fr < n; fr++ {
+			for to := fr + 1; to < n; to++ {
+				if r.Intn(3) == 0 {
+					a[fr] = append(a[fr], graph.Half{graph.NI(to), graph.LI(r.Intn(5))})
+				}
+			}
+		}
+		c, err := graph.LabeledDirected{a}.CPM(w)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// brute force: enumerate all maximal source to sink paths
+		hasIn := make([]bool, n)
+		for _, to := range a {
+			for _, h := range to {
+				hasIn[h.To] = true
+			}
+		}
+		dur := 0.
+		var all []graph.LabeledPath
+		var p []graph.Half
+		var df func(graph.NI, graph.NI, float64)
+		df = func(start, fr graph.NI, d float64) {
+			if len(a[fr]) == 0 {
+				all = append(all, graph.LabeledPath{start, append([]graph.Half{}, p...)})
+				dur = math.Max(dur, d)
+				return
+			}
+			for _, h := range a[fr] {
+				p = append(p, h)
+				df(start, h.To, d+w(h.Label))
+				p = p[:len(p)-1]
+			}
+		}
+		for s := range a {
+			if !hasIn[s] {
+				df(graph.NI(s), graph.NI(s), 0)
+			}
+		}
+		if c.Duration != dur {
+			t.Fatalf("duration %v, want %v", c.Duration, dur)
+		}
+		// longest distance to and from each node
+		to := make([]float64, n)
+		from := make([]float64, n)
+		for fr := n - 1; fr >= 0; fr-- {
+			for _, h := range a[fr] {
+				from[fr] = math.Max(from[fr], w(h.Label)+from[h.To])
+			}
+		}
+		for fr := 0; fr < n; fr++ {
+			for _, h := range a[fr] {
+				to[h.To] = math.Max(to[h.To], to[fr]+w(h.Label))
+			}
+		}
+		for v := 0; v < n; v++ {
+			if c.Early[v] != to[v] || c.Late[v] != dur-from[v] ||
+				c.Slack[v] != c.Late[v]-c.Early[v] {
+				t.Fatalf("node %d: early %v late %v", v, c.Early[v], c.Late[v])
+			}
+			for x, h := range a[v] {
+				if c.ArcEarly[v][x] != to[v] ||
+					c.ArcLate[v][x] != dur-from[h.To]-w(h.Label) ||
+					c.ArcSlack[v][x] != c.ArcLate[v][x]-c.ArcEarly[v][x] {
+					t.Fatalf("arc %d->%v", v, h)
+				}
+			}
+		}
+		want := map[string]bool{}
+		for _, p := range all {
+			d := 0.
+			for _, h := range p.Path {
+				d += w(h.Label)
+			}
+			if d == dur {
+				want[fmt.Sprint(p)] = true
+			}
+		}
+		got := map[string]bool{}
+		c.CriticalPaths(func(p graph.LabeledPath) bool {
+			s := fmt.Sprint(p)
+			if got[s] {
+				t.Fatal("duplicate", s)
+			}
+			got[s] = true
+			return true
+		})
+		if len(got) != len(want) {
+			t.Fatalf("got %v want %v", got, want)
+		}
+		for s := range want {
+			if !got[s] {
+				t.Fatal("missing", s)
+			}
+		}
+	}
+	// cyclic graph
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1}},
+		1: {{To: 0}},
+	}}
+	if _, err := g.CPM(w); err == nil {
+		t.Fatal("no error for cyclic graph")
+	}
+}
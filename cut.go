@@ -0,0 +1,610 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+	"sort"
+
+	"github.com/soniakeys/bits"
+)
+
+// cut.go has minimum cut and connectivity algorithms.
+
+// GomoryHu computes a Gomory-Hu tree of an undirected graph.
+//
+// A Gomory-Hu tree is a weighted tree on the nodes of g that represents
+// minimum cuts between all pairs of nodes.  For any two nodes a and b, the
+// weight of a minimum a-b cut in g is the minimum weight of an edge on the
+// tree path between a and b.  Further, removing that tree edge partitions
+// the nodes of the tree into the two sides of a minimum a-b cut in g.
+//
+// Edge weights of g are given by WeightFunc w and must be non-negative.
+// Loops are ignored, parallel edges contribute their weights individually.
+//
+// The tree is returned as FromList t, rooted at node 0, with the Leaves and
+// Len members populated.  The tree edge from each node n to t.Paths[n].From
+// has weight cutWt[n].  For the root, cutWt is +Inf.  If g is not
+// connected, the tree still spans all nodes, but with some edges of
+// weight 0.
+//
+// See GomoryHuMinCut for querying the tree.
+//
+// The algorithm is that of Gusfield, which computes n-1 maximum flows on g
+// without contracting nodes.  Time complexity is that of n-1 runs of
+// Dinic's algorithm, O(n³m) worst case but typically much faster.
+func (g LabeledUndirected) GomoryHu(w WeightFunc) (t FromList, cutWt []float64) {
+	// Ref: "Very Simple Methods for All Pairs Network Flow Analysis",
+	// Dan Gusfield, SIAM J. Computing 19 (1990).
+	a := g.LabeledAdjacencyList
+	net := newFlowNet(len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to.To {
+				wt := w(to.Label)
+				net.addArc(NI(fr), to.To, wt, wt)
+			}
+		}
+	}
+	t = NewFromList(len(a))
+	cutWt = make([]float64, len(a))
+	p := t.Paths
+	for n := range p {
+		p[n].From = 0
+	}
+	if len(a) > 0 {
+		p[0].From = -1
+		cutWt[0] = math.Inf(1)
+	}
+	for s := NI(1); int(s) < len(a); s++ {
+		u := p[s].From
+		net.reset()
+		c := net.maxFlow(s, u, math.Inf(1))
+		x := net.sourceSide(s)
+		cutWt[s] = c
+		for i := range p {
+			if NI(i) != s && x.Bit(i) == 1 && p[i].From == u {
+				p[i].From = s
+			}
+		}
+		if pu := p[u].From; pu >= 0 && x.Bit(int(pu)) == 1 {
+			p[s].From = pu
+			p[u].From = s
+			cutWt[s] = cutWt[u]
+			cutWt[u] = c
+		}
+	}
+	t.RecalcLeaves()
+	t.RecalcLen()
+	return
+}
+
+// GomoryHuMinCut queries a Gomory-Hu tree for the weight of a minimum cut
+// between nodes a and b.
+//
+// Arguments t and cutWt should be as returned by LabeledUndirected.GomoryHu.
+// The result is the minimum cutWt along the tree path between a and b.
+// If a and b are the same node, the result is +Inf.
+func GomoryHuMinCut(t FromList, cutWt []float64, a, b NI) float64 {
+	m := math.Inf(1)
+	c := t.CommonStart(a, b)
+	p := t.Paths
+	for ; a != c; a = p[a].From {
+		m = math.Min(m, cutWt[a])
+	}
+	for ; b != c; b = p[b].From {
+		m = math.Min(m, cutWt[b])
+	}
+	return m
+}
+
+// StoerWagner finds a global minimum cut of an undirected graph.
+//
+// A cut partitions the nodes of g into two non-empty sets.  The weight of
+// the cut is the sum of weights of edges with one end point in each set.
+// StoerWagner finds a cut of minimum weight over all possible partitions.
+// Edge weights are given by WeightFunc w and must be non-negative.  Loops
+// are ignored, parallel edges contribute their weights individually.
+//
+// Returned is the weight of the cut and a partition of the nodes of g.
+// Nodes with bits set in partition are on one side of the cut, nodes
+// with bits clear are on the other.  If g is not connected, the minimum
+// cut weight is 0 and partition will hold the union of one or more, but not
+// all, connected components of g.
+//
+// If g has fewer than two nodes there is no cut.  In this case returned
+// weight is +Inf and partition is all zeros.
+//
+// Time complexity is O(nm log n) where n is the number of nodes and m is
+// the number of edges.
+//
+// See also KargerStein.
+func (g LabeledUndirected) StoerWagner(w WeightFunc) (weight float64, partition bits.Bits) {
+	// Ref: "A Simple Min-Cut Algorithm", Mechthild Stoer and Frank Wagner,
+	// J. ACM 44 (1997).
+	a := g.LabeledAdjacencyList
+	partition = bits.New(len(a))
+	weight = math.Inf(1)
+	if len(a) < 2 {
+		return
+	}
+	type swArc struct {
+		to NI
+		wt float64
+	}
+	adj := make([][]swArc, len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			if to.To != NI(fr) {
+				adj[fr] = append(adj[fr], swArc{to.To, w(to.Label)})
+			}
+		}
+	}
+	// merged nodes are represented by disjoint set roots.  members of
+	// each set are kept in a circular list by next.
+	ds := NewUnionFind(len(a))
+	next := make([]NI, len(a))
+	for n := range next {
+		next[n] = NI(n)
+	}
+	nodes := make([]swNode, len(a))
+	h := make(swHeap, 0, len(a))
+	for nActive := len(a); nActive > 1; nActive-- {
+		// a phase: find a maximum adjacency ordering of the active nodes.
+		h = h[:0]
+		for n := range a {
+			if ds.Find(NI(n)) == NI(n) {
+				nodes[n] = swNode{nx: NI(n), fx: len(h)}
+				h = append(h, &nodes[n])
+			}
+		}
+		var s, t NI = -1, -1
+		for len(h) > 0 {
+			u := heap.Pop(&h).(*swNode)
+			u.fx = -1
+			s, t = t, u.nx
+			for _, e := range adj[u.nx] {
+				if v := &nodes[ds.Find(e.to)]; v.fx >= 0 {
+					v.key += e.wt
+					heap.Fix(&h, v.fx)
+				}
+			}
+		}
+		// cut of the phase separates t from all other nodes.
+		if c := nodes[t].key; c < weight {
+			weight = c
+			partition.ClearAll()
+			for n := t; ; {
+				partition.SetBit(int(n), 1)
+				if n = next[n]; n == t {
+					break
+				}
+			}
+		}
+		// merge s and t
+		ds.Union(s, t)
+		r := ds.Find(s)
+		other := s
+		if r == s {
+			other = t
+		}
+		adj[r] = append(adj[r], adj[other]...)
+		adj[other] = nil
+		next[s], next[t] = next[t], next[s]
+	}
+	return
+}
+
+type swNode struct {
+	nx  NI
+	key float64
+	fx  int
+}
+
+type swHeap []*swNode
+
+func (h swHeap) Len() int           { return len(h) }
+func (h swHeap) Less(i, j int) bool { return h[i].key > h[j].key }
+func (h swHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].fx = i
+	h[j].fx = j
+}
+func (p *swHeap) Push(x interface{}) {
+	nd := x.(*swNode)
+	nd.fx = len(*p)
+	*p = append(*p, nd)
+}
+func (p *swHeap) Pop() interface{} {
+	r := *p
+	last := len(r) - 1
+	*p = r[:last]
+	return r[last]
+}
+
+// KargerStein finds a global minimum cut of an undirected graph with high
+// probability.
+//
+// KargerStein is a randomized alternative to StoerWagner.  Arguments,
+// return values, and special cases are the same as for StoerWagner except
+// for the additional arguments trials and r.
+//
+// The result of a single trial is a minimum cut with probability
+// Ω(1/log n).  The method runs the specified number of trials and returns
+// the best cut found.  If trials is <= 0, ⌈log₂ n⌉² trials are run,
+// making the result a minimum cut with high probability.
+//
+// If Rand r is nil, the rand package default shared source is used.
+//
+// Contraction merges parallel edges, so that a contracted graph of t nodes
+// has at most t(t-1)/2 edges.  Time complexity of each trial is then
+// O(m log m + n² log n), and of the default number of trials
+// O((m log m + n² log n) log² n).  This is generally slower than
+// StoerWagner, particularly for sparse graphs.
+func (g LabeledUndirected) KargerStein(w WeightFunc, trials int, r *rand.Rand) (weight float64, partition bits.Bits) {
+	// Ref: "A New Approach to the Minimum Cut Problem", David Karger and
+	// Clifford Stein, J. ACM 43 (1996).
+	a := g.LabeledAdjacencyList
+	partition = bits.New(len(a))
+	weight = math.Inf(1)
+	if len(a) < 2 {
+		return
+	}
+	// a disconnected graph has a cut of weight 0.  checking for this up
+	// front means contraction can always proceed to the target size.
+	if order, _, b := g.ConnectedComponentBits()(); order < len(a) {
+		return 0, b
+	}
+	uf := rand.Float64
+	if r != nil {
+		uf = r.Float64
+	}
+	var el []ksEdge
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to.To {
+				el = append(el, ksEdge{NI(fr), to.To, w(to.Label), 0})
+			}
+		}
+	}
+	if trials <= 0 {
+		l := int(math.Ceil(math.Log2(float64(len(a)))))
+		trials = l * l
+	}
+	ks := ksState{uf: uf}
+	for ; trials > 0; trials-- {
+		if wt, side := ks.recurse(el, len(a)); wt < weight {
+			weight, partition = wt, side
+		}
+	}
+	return
+}
+
+type ksEdge struct {
+	n1, n2 NI
+	wt     float64
+	key    float64
+}
+
+type ksState struct {
+	uf func() float64
+}
+
+// recurse is the recursive contraction of Karger and Stein.  edge list el
+// is over nodes 0 through n-1.  Returned is the weight of the best cut
+// found and the partition of the n nodes.
+func (ks ksState) recurse(el []ksEdge, n int) (float64, bits.Bits) {
+	if n <= 6 {
+		return ksBrute(el, n)
+	}
+	t := int(math.Ceil(1 + float64(n)/math.Sqrt2))
+	bestWt := math.Inf(1)
+	var best bits.Bits
+	for i := 0; i < 2; i++ {
+		cl, lab := ks.contract(el, n, t)
+		wt, side := ks.recurse(cl, t)
+		if wt < bestWt {
+			bestWt = wt
+			best = bits.New(n)
+			for x, l := range lab {
+				if side.Bit(int(l)) == 1 {
+					best.SetBit(x, 1)
+				}
+			}
+		}
+	}
+	return bestWt, best
+}
+
+// contract randomly contracts edges of el until t nodes remain.  Returned
+// is the contracted edge list and the mapping from nodes of el to nodes of
+// the contracted graph.
+//
+// Contracting edges in order of exponentially distributed random keys with
+// rates given by edge weights is equivalent to repeatedly contracting an
+// edge chosen with probability proportional to its weight.
+func (ks ksState) contract(el []ksEdge, n, t int) ([]ksEdge, []NI) {
+	el = append([]ksEdge{}, el...)
+	for i := range el {
+		if el[i].wt > 0 {
+			el[i].key = -math.Log(1-ks.uf()) / el[i].wt
+		} else {
+			el[i].key = math.Inf(1)
+		}
+	}
+	sort.Slice(el, func(i, j int) bool { return el[i].key < el[j].key })
+	ds := NewUnionFind(n)
+	for _, e := range el {
+		if ds.Count() == t {
+			break
+		}
+		ds.Union(e.n1, e.n2)
+	}
+	lab := make([]NI, n)
+	nx := make([]NI, n)
+	for i := range nx {
+		nx[i] = -1
+	}
+	t = 0
+	for i := range lab {
+		r := ds.Find(NI(i))
+		if nx[r] < 0 {
+			nx[r] = NI(t)
+			t++
+		}
+		lab[i] = nx[r]
+	}
+	// merge parallel edges so the contracted graph has at most t(t-1)/2
+	// edges.
+	cl := el[:0]
+	px := map[[2]NI]int{}
+	for _, e := range el {
+		n1, n2 := lab[e.n1], lab[e.n2]
+		switch {
+		case n1 == n2:
+			continue
+		case n1 > n2:
+			n1, n2 = n2, n1
+		}
+		if x, ok := px[[2]NI{n1, n2}]; ok {
+			cl[x].wt += e.wt
+			continue
+		}
+		px[[2]NI{n1, n2}] = len(cl)
+		cl = append(cl, ksEdge{n1, n2, e.wt, 0})
+	}
+	return cl, lab
+}
+
+// ksBrute finds a minimum cut of a small graph by trying all partitions.
+func ksBrute(el []ksEdge, n int) (float64, bits.Bits) {
+	bestWt := math.Inf(1)
+	best := 0
+	// node n-1 is always on the zero side, so each partition is tried once.
+	for s := 1; s < 1<<uint(n-1); s++ {
+		wt := 0.
+		for _, e := range el {
+			if s>>uint(e.n1)&1 != s>>uint(e.n2)&1 {
+				wt += e.wt
+			}
+		}
+		if wt < bestWt {
+			bestWt = wt
+			best = s
+		}
+	}
+	b := bits.New(n)
+	for x := 0; x < n-1; x++ {
+		b.SetBit(x, best>>uint(x)&1)
+	}
+	return bestWt, b
+}
+
+// EdgeConnectivity computes the edge connectivity of an undirected graph.
+//
+// The edge connectivity of a graph is the minimum number of edges that
+// must be removed to disconnect it.  Parallel edges count individually,
+// loops are ignored.  A graph that is not connected, or that has fewer than
+// two nodes, has edge connectivity 0.
+//
+// The method computes n-1 unit capacity maximum flows.
+//
+// See also IsKEdgeConnected, which can be faster when only a lower bound
+// on connectivity is needed.
+func (g Undirected) EdgeConnectivity() int {
+	return g.edgeConnectivity(math.MaxInt32)
+}
+
+// IsKConnected tests whether an undirected graph is k-vertex-connected.
+//
+// A graph is k-vertex-connected, or simply k-connected, if it has more than
+// k nodes and remains connected whenever fewer than k nodes are removed.
+// Every graph is 0-connected.  A graph is 1-connected if it is connected
+// and has at least two nodes.  A graph is 2-connected, or biconnected, if
+// additionally it has at least three nodes and no articulation points.
+//
+// Cases k = 1 and k = 2 are handled with simple linear time traversals.
+// For k > 2, IsKConnected checks minimum degree and then computes maximum
+// flows as in VertexConnectivity, but limited to flows of k and a limited
+// number of source nodes.
+//
+// See also VertexConnectivity and IsKEdgeConnected.
+func (g Undirected) IsKConnected(k int) bool {
+	a := g.AdjacencyList
+	switch {
+	case k <= 0:
+		return true
+	case len(a) <= k:
+		return false
+	case k == 1:
+		return g.IsConnected()
+	case k == 2:
+		if !g.IsConnected() {
+			return false
+		}
+		ok := true
+		g.BlockCut(
+			func([]Edge) bool { return true },
+			func(NI) bool {
+				ok = false
+				return false
+			},
+			func(NI) bool {
+				ok = false
+				return false
+			})
+		return ok
+	}
+	return g.vertexConnectivity(k) >= k
+}
+
+// IsKEdgeConnected tests whether an undirected graph is k-edge-connected.
+//
+// A graph is k-edge-connected if it has at least two nodes and remains
+// connected whenever fewer than k edges are removed.  Every graph is
+// 0-edge-connected.
+//
+// Cases k = 1 and k = 2 are handled with simple linear time traversals,
+// testing for connectivity and bridges.  For k > 2, IsKEdgeConnected checks
+// minimum degree and then computes maximum flows as in EdgeConnectivity,
+// but limited to flows of k.
+//
+// See also EdgeConnectivity and IsKConnected.
+func (g Undirected) IsKEdgeConnected(k int) bool {
+	switch {
+	case k <= 0:
+		return true
+	case g.Order() < 2 || !g.IsConnected():
+		return false
+	case k == 1:
+		return true
+	case k == 2:
+		ok := true
+		g.Bridges(func(Edge) bool {
+			ok = false
+			return false
+		})
+		return ok
+	}
+	return g.edgeConnectivity(k) >= k
+}
+
+// edgeConnectivity computes edge connectivity, or any value >= limit if
+// edge connectivity is at least limit.
+func (g Undirected) edgeConnectivity(limit int) int {
+	a := g.AdjacencyList
+	if len(a) < 2 {
+		return 0
+	}
+	// minimum degree is an upper bound
+	c := limit
+	for fr, to := range a {
+		d := 0
+		for _, to := range to {
+			if to != NI(fr) {
+				d++
+			}
+		}
+		if d < c {
+			c = d
+		}
+	}
+	if c == 0 {
+		return 0
+	}
+	net := newFlowNet(len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			if NI(fr) < to {
+				net.addArc(NI(fr), to, 1, 1)
+			}
+		}
+	}
+	// some minimum cut separates node 0 from some other node.
+	for t := 1; t < len(a); t++ {
+		net.reset()
+		if f := int(net.maxFlow(0, NI(t), float64(c))); f < c {
+			c = f
+		}
+	}
+	return c
+}
+
+// VertexConnectivity computes the vertex connectivity of an undirected
+// graph.
+//
+// The vertex connectivity of a graph is the minimum number of nodes that
+// must be removed to disconnect it or to leave a single node.  Parallel
+// edges and loops are ignored.  A graph that is not connected has vertex
+// connectivity 0.  The complete graph on n nodes has vertex connectivity
+// n-1.
+//
+// The algorithm is that of Even, which computes maximum flows between
+// nonadjacent pairs of nodes in a node-split network.
+//
+// See also IsKConnected, which can be faster when only a lower bound on
+// connectivity is needed.
+func (g Undirected) VertexConnectivity() int {
+	return g.vertexConnectivity(math.MaxInt32)
+}
+
+// vertexConnectivity computes vertex connectivity, or any value >= limit
+// if vertex connectivity is at least limit.
+func (g Undirected) vertexConnectivity(limit int) int {
+	// Ref: "An Algorithm for Determining Whether the Connectivity of a
+	// Graph is at Least k", Shimon Even, SIAM J. Computing 4 (1975).
+	a := g.AdjacencyList
+	n := len(a)
+	if n < 2 {
+		return 0
+	}
+	// minimum degree, also n-1, are upper bounds
+	c := limit
+	if n-1 < c {
+		c = n - 1
+	}
+	nb := make([]bits.Bits, n)
+	for fr, to := range a {
+		b := bits.New(n)
+		for _, to := range to {
+			if to != NI(fr) {
+				b.SetBit(int(to), 1)
+			}
+		}
+		if d := b.OnesCount(); d < c {
+			c = d
+		}
+		nb[fr] = b
+	}
+	if c == 0 {
+		return 0
+	}
+	// node n is split into n "in" and n+len(a) "out" with an arc of
+	// capacity 1.  edges become arcs from out to in with capacity n.
+	net := newFlowNet(2 * n)
+	for fr := range a {
+		net.addArc(NI(fr), NI(fr+n), 1, 0)
+		nb[fr].IterateOnes(func(to int) bool {
+			net.addArc(NI(fr+n), NI(to), float64(n), 0)
+			return true
+		})
+	}
+	// some minimum separator S has |S| = c.  some node among the first
+	// c+1 is not in S, and some node on the other side of S is not
+	// adjacent to it.
+	for i := 0; i <= c && i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if nb[i].Bit(j) == 1 {
+				continue
+			}
+			net.reset()
+			if f := int(net.maxFlow(NI(i+n), NI(j), float64(c))); f < c {
+				c = f
+			}
+		}
+	}
+	return c
+}
@@ -0,0 +1,364 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleGomoryHuMinCut() {
+	//    (3)     (1)     (4)
+	//  0-----1-------2------3
+	//   \   /         \    /
+	//  (2)(2)         (3)(2)
+	//     4              5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{0, 4}, 2)
+	g.AddEdge(graph.Edge{1, 4}, 2)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{2, 3}, 4)
+	g.AddEdge(graph.Edge{2, 5}, 3)
+	g.AddEdge(graph.Edge{3, 5}, 2)
+	t, cutWt := g.GomoryHu(func(l graph.LI) float64 { return float64(l) })
+	fmt.Println(graph.GomoryHuMinCut(t, cutWt, 0, 1))
+	fmt.Println(graph.GomoryHuMinCut(t, cutWt, 4, 5))
+	fmt.Println(graph.GomoryHuMinCut(t, cutWt, 2, 3))
+	// Output:
+	// 5
+	// 1
+	// 6
+}
+
+func ExampleUndirected_EdgeConnectivity() {
+	// 0---1---2
+	// |\ /|\ /|
+	// | X | X |
+	// |/ \|/ \|
+	// 3---4---5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(1, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(1, 5)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	fmt.Println(g.EdgeConnectivity())
+	fmt.Println(g.IsKEdgeConnected(3))
+	// Output:
+	// 3
+	// true
+}
+
+func ExampleUndirected_IsKConnected() {
+	// 0---1---2
+	// |\ /|\ /|
+	// | X | X |
+	// |/ \|/ \|
+	// 3---4---5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(1, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(1, 5)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	fmt.Println(g.IsKConnected(2), g.IsKConnected(3))
+	// Output:
+	// true false
+}
+
+func ExampleUndirected_VertexConnectivity() {
+	// 0---1---2
+	// |\ /|\ /|
+	// | X | X |
+	// |/ \|/ \|
+	// 3---4---5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(3, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(0, 3)
+	g.AddEdge(0, 4)
+	g.AddEdge(1, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(1, 5)
+	g.AddEdge(2, 4)
+	g.AddEdge(2, 5)
+	// removing nodes 1 and 4 disconnects the graph
+	fmt.Println(g.VertexConnectivity())
+	// Output:
+	// 2
+}
+
+func ExampleLabeledUndirected_GomoryHu() {
+	//    (3)     (1)     (4)
+	//  0-----1-------2------3
+	//   \   /         \    /
+	//  (2)(2)         (3)(2)
+	//     4              5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{0, 4}, 2)
+	g.AddEdge(graph.Edge{1, 4}, 2)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{2, 3}, 4)
+	g.AddEdge(graph.Edge{2, 5}, 3)
+	g.AddEdge(graph.Edge{3, 5}, 2)
+	t, cutWt := g.GomoryHu(func(l graph.LI) float64 { return float64(l) })
+	fmt.Println("node  from  cut weight")
+	for n, e := range t.Paths {
+		fmt.Printf("%d  %4d  %6g\n", n, e.From, cutWt[n])
+	}
+	// Output:
+	// node  from  cut weight
+	// 0    -1    +Inf
+	// 1     0       5
+	// 2     1       1
+	// 3     2       6
+	// 4     0       4
+	// 5     2       5
+}
+
+func ExampleLabeledUndirected_KargerStein() {
+	//    (3)     (1)     (4)
+	//  0-----1-------2------3
+	//   \   /         \    /
+	//  (2)(2)         (3)(2)
+	//     4              5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{0, 4}, 2)
+	g.AddEdge(graph.Edge{1, 4}, 2)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{2, 3}, 4)
+	g.AddEdge(graph.Edge{2, 5}, 3)
+	g.AddEdge(graph.Edge{3, 5}, 2)
+	w := func(l graph.LI) float64 { return float64(l) }
+	wt, p := g.KargerStein(w, 0, rand.New(rand.NewSource(1)))
+	fmt.Println(wt, p.Slice())
+	// Output:
+	// 1 [0 1 4]
+}
+
+func ExampleLabeledUndirected_StoerWagner() {
+	//    (3)     (1)     (4)
+	//  0-----1-------2------3
+	//   \   /         \    /
+	//  (2)(2)         (3)(2)
+	//     4              5
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 3)
+	g.AddEdge(graph.Edge{0, 4}, 2)
+	g.AddEdge(graph.Edge{1, 4}, 2)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{2, 3}, 4)
+	g.AddEdge(graph.Edge{2, 5}, 3)
+	g.AddEdge(graph.Edge{3, 5}, 2)
+	wt, p := g.StoerWagner(func(l graph.LI) float64 { return float64(l) })
+	fmt.Println(wt, p.Slice())
+	// Output:
+	// 1 [2 3 5]
+}
+
+func TestGomoryHu(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 100; i++ {
+		n := 2 + r.Intn(10)
+		var g graph.LabeledUndirected
+		g.AddEdge(graph.Edge{0, graph.NI(n - 1)}, graph.LI(r.Intn(5)))
+		for j := 3 * n / 2; j > 0; j-- {
+			e := graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))}
+			g.AddEdge(e, graph.LI(1+r.Intn(5)))
+		}
+		tr, cutWt := g.GomoryHu(w)
+		// each tree edge separates the nodes of a cut of the edge weight
+		for x := 1; x < n; x++ {
+			in := make([]bool, n)
+			for y := range in {
+				in[y] = tr.CommonStart(graph.NI(x), graph.NI(y)) == graph.NI(x)
+			}
+			c := 0.
+			g.Edges(func(e graph.LabeledEdge) {
+				if in[e.N1] != in[e.N2] {
+					c += w(e.LI)
+				}
+			})
+			if c != cutWt[x] {
+				t.Fatalf("tree edge from %d has weight %g, cut %g", x, cutWt[x], c)
+			}
+		}
+		for a := 0; a < n; a++ {
+			for b := a + 1; b < n; b++ {
+				// brute force min a-b cut
+				want := -1.
+				for s := 0; s < 1<<uint(n); s++ {
+					if s>>uint(a)&1 == 0 || s>>uint(b)&1 == 1 {
+						continue
+					}
+					c := 0.
+					g.Edges(func(e graph.LabeledEdge) {
+						if s>>uint(e.N1)&1 != s>>uint(e.N2)&1 {
+							c += w(e.LI)
+						}
+					})
+					if want < 0 || c < want {
+						want = c
+					}
+				}
+				got := graph.GomoryHuMinCut(tr, cutWt, graph.NI(a), graph.NI(b))
+				if got != want {
+					t.Fatalf("min cut %d-%d = %g, want %g", a, b, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestMinCut(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 100; i++ {
+		n := 2 + r.Intn(10)
+		var g graph.LabeledUndirected
+		g.AddEdge(graph.Edge{0, graph.NI(n - 1)}, graph.LI(r.Intn(5)))
+		for j := 3 * n / 2; j > 0; j-- {
+			e := graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))}
+			g.AddEdge(e, graph.LI(1+r.Intn(5)))
+		}
+		// brute force
+		want := -1.
+		for s := 1; s < 1<<uint(n-1); s++ {
+			c := 0.
+			g.Edges(func(e graph.LabeledEdge) {
+				if s>>uint(e.N1)&1 != s>>uint(e.N2)&1 {
+					c += w(e.LI)
+				}
+			})
+			if want < 0 || c < want {
+				want = c
+			}
+		}
+		check := func(name string, wt float64, p []int) {
+			if wt != want {
+				t.Fatalf("%s weight = %g, want %g", name, wt, want)
+			}
+			if len(p) == 0 || len(p) == n {
+				t.Fatalf("%s partition %v not a cut", name, p)
+			}
+			in := make([]bool, n)
+			for _, x := range p {
+				in[x] = true
+			}
+			c := 0.
+			g.Edges(func(e graph.LabeledEdge) {
+				if in[e.N1] != in[e.N2] {
+					c += w(e.LI)
+				}
+			})
+			if c != wt {
+				t.Fatalf("%s partition has weight %g, want %g", name, c, wt)
+			}
+		}
+		wt, p := g.StoerWagner(w)
+		check("StoerWagner", wt, p.Slice())
+		wt, p = g.KargerStein(w, 20, r)
+		check("KargerStein", wt, p.Slice())
+	}
+}
+
+func TestConnectivity(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(8)
+		g := graph.GnmUndirected(n, r.Intn(n*(n-1)/2+1), r)
+		if i%4 == 0 && n > 1 {
+			g.AddEdge(0, 1) // parallel edge or new edge
+		}
+		// brute force vertex connectivity: smallest node set whose removal
+		// disconnects the graph.
+		wantV := n - 1
+		if n < 2 {
+			wantV = 0
+		}
+		for s := 0; s < 1<<uint(n); s++ {
+			k := 0
+			for x := s; x > 0; x &= x - 1 {
+				k++
+			}
+			if k >= wantV || n-k < 2 {
+				continue
+			}
+			var h graph.Undirected
+			h.AdjacencyList = make(graph.AdjacencyList, n)
+			g.Edges(func(e graph.Edge) {
+				if s>>uint(e.N1)&1 == 0 && s>>uint(e.N2)&1 == 0 {
+					h.AddEdge(e.N1, e.N2)
+				}
+			})
+			rest := make(graph.AdjacencyList, 0, n-k)
+			m := make([]graph.NI, n)
+			for x := 0; x < n; x++ {
+				if s>>uint(x)&1 == 0 {
+					m[x] = graph.NI(len(rest))
+					rest = append(rest, nil)
+				}
+			}
+			for fr, to := range h.AdjacencyList {
+				for _, to := range to {
+					rest[m[fr]] = append(rest[m[fr]], m[to])
+				}
+			}
+			if !(graph.Undirected{rest}).IsConnected() {
+				wantV = k
+			}
+		}
+		// brute force edge connectivity: minimum cut
+		wantE := 0
+		if n > 1 {
+			wantE = -1
+			for s := 1; s < 1<<uint(n-1); s++ {
+				c := 0
+				g.Edges(func(e graph.Edge) {
+					if s>>uint(e.N1)&1 != s>>uint(e.N2)&1 {
+						c++
+					}
+				})
+				if wantE < 0 || c < wantE {
+					wantE = c
+				}
+			}
+		}
+		if got := g.VertexConnectivity(); got != wantV {
+			t.Fatalf("VertexConnectivity = %d, want %d %v", got, wantV, g)
+		}
+		if got := g.EdgeConnectivity(); got != wantE {
+			t.Fatalf("EdgeConnectivity = %d, want %d %v", got, wantE, g)
+		}
+		for k := 0; k <= n; k++ {
+			if got := g.IsKConnected(k); got != (k <= wantV) {
+				t.Fatalf("IsKConnected(%d) = %t, want %t %v", k, got, !got, g)
+			}
+			if got := g.IsKEdgeConnected(k); got != (k <= wantE) {
+				t.Fatalf("IsKEdgeConnected(%d) = %t, want %t %v", k, got, !got, g)
+			}
+		}
+	}
+}
@@ -5,6 +5,9 @@ package graph
 
 import (
 	"math"
+	"sort"
+
+	"github.com/soniakeys/bits"
 )
 
 // dir.go has methods specific to directed graphs, types Directed and
@@ -123,6 +126,99 @@ func (g Directed) Cycles(emit func([]NI) bool) {
 	}
 }
 
+// CoffmanGraham partitions a directed acyclic graph into layers of at most
+// w nodes each.
+//
+// Arcs of the result go from earlier layers to later layers, so nodes of a
+// layer depend only on nodes in earlier layers.  This is a schedule of unit
+// time tasks on w processors.  For w = 2 the number of layers is minimum.
+// For larger w the number of layers is at most 2 - 2/w times the minimum.
+// If w < 1 the width is not bounded.  Nodes within a layer are in
+// ascending order.
+//
+// If g is cyclic, layers will be nil and cycle will be the path of a found
+// cycle, as returned by Topological.
+//
+// Ref: "Optimal scheduling for two-processor systems", E. G. Coffman and
+// R. L. Graham, Acta Informatica (1972).
+func (g Directed) CoffmanGraham(w int) (layers [][]NI, cycle []NI) {
+	r, cycle := g.TransitiveReduction()
+	if cycle != nil {
+		return nil, cycle
+	}
+	a := r.AdjacencyList
+	tr, _ := r.Transpose()
+	// label nodes 1..n, choosing at each step the node with all
+	// predecessors labeled with the lexicographically least decreasing
+	// sequence of predecessor labels.
+	label := make([]int, len(a))
+	pl := make([][]int, len(a)) // predecessor labels, decreasing
+	less := func(x, y []int) bool {
+		for i := 0; i < len(x) && i < len(y); i++ {
+			if x[i] != y[i] {
+				return x[i] < y[i]
+			}
+		}
+		return len(x) < len(y)
+	}
+	for l := 1; l <= len(a); l++ {
+		b := NI(-1)
+		for n, fr := range tr.AdjacencyList {
+			if label[n] > 0 || len(pl[n]) < len(fr) {
+				continue
+			}
+			if b < 0 || less(pl[n], pl[b]) {
+				b = NI(n)
+			}
+		}
+		label[b] = l
+		for _, to := range a[b] {
+			pl[to] = append([]int{l}, pl[to]...)
+		}
+	}
+	// fill layers from the bottom, choosing at each step the node with the
+	// greatest label that has all successors in lower layers.
+	layer := make([]int, len(a))
+	for n := range layer {
+		layer[n] = -1
+	}
+	var cur []NI
+	for placed := 0; placed < len(a); {
+		b := NI(-1)
+		if w < 1 || len(cur) < w {
+		nodes:
+			for n, to := range a {
+				if layer[n] >= 0 || b >= 0 && label[n] < label[b] {
+					continue
+				}
+				for _, to := range to {
+					if layer[to] < 0 || layer[to] == len(layers) {
+						continue nodes
+					}
+				}
+				b = NI(n)
+			}
+		}
+		if b < 0 {
+			sort.Slice(cur, func(i, j int) bool { return cur[i] < cur[j] })
+			layers = append(layers, cur)
+			cur = nil
+			continue
+		}
+		layer[b] = len(layers)
+		cur = append(cur, b)
+		placed++
+	}
+	if len(cur) > 0 {
+		sort.Slice(cur, func(i, j int) bool { return cur[i] < cur[j] })
+		layers = append(layers, cur)
+	}
+	for i, j := 0, len(layers)-1; i < j; i, j = i+1, j-1 {
+		layers[i], layers[j] = layers[j], layers[i]
+	}
+	return layers, nil
+}
+
 // DAGMaxLenPath finds a maximum length path in a directed acyclic graph.
 //
 // Argument ordering must be a topological ordering of g.
@@ -187,6 +283,93 @@ func (g Directed) FromList() (f *FromList, simpleForest bool) {
 	return &FromList{Paths: paths}, simpleForest
 }
 
+// Layers partitions a directed acyclic graph into layers, or antichains.
+//
+// Layer 0 holds the nodes with no in-arcs.  Each other node is in the layer
+// after the latest layer of its predecessors, so that nodes of a layer
+// depend only on nodes in earlier layers and can be processed in parallel.
+// The number of layers is the number of nodes in a longest path.  Nodes
+// within a layer are in ascending order.
+//
+// The layering is computed over the ordering of TopologicalKahn.  If g is
+// cyclic, layers will be nil and cycle will be the path of a found cycle,
+// as returned by Topological.
+//
+// See also CoffmanGraham for layers of bounded width.
+func (g Directed) Layers() (layers [][]NI, cycle []NI) {
+	tr, _ := g.Transpose()
+	ordering, cycle := g.TopologicalKahn(tr)
+	if cycle != nil {
+		_, cycle = g.Topological()
+		return nil, cycle
+	}
+	level := make([]int, len(ordering))
+	for _, n := range ordering {
+		for _, to := range g.AdjacencyList[n] {
+			if l := level[n] + 1; l > level[to] {
+				level[to] = l
+			}
+		}
+	}
+	// iterating node numbers leaves each layer in ascending order
+	for n, l := range level {
+		for len(layers) <= l {
+			layers = append(layers, nil)
+		}
+		layers[l] = append(layers[l], NI(n))
+	}
+	return layers, nil
+}
+
+// MinimumEquivalentGraph returns a graph with the fewest arcs having the
+// same reachability as g.
+//
+// G may be cyclic.  The result is constructed from the condensation of g.
+// Each strongly connected component of more than one node is represented
+// by a single cycle through its nodes, in the order of nodes in the
+// component.  Components are connected by one arc of g for each arc of the
+// transitive reduction of the condensation.
+//
+// Note that arcs of a component cycle may not be arcs of g.  Finding a
+// minimum equivalent subgraph of g is NP-hard.  The result here is the
+// transitive reduction of Aho, Garey, and Ullman, which is a subgraph of g
+// when g is acyclic.  Loops and parallel arcs are not retained.
+//
+// Ref: "The Transitive Reduction of a Directed Graph", A. V. Aho,
+// M. R. Garey, and J. D. Ullman, SIAM J. Comput. (1972).
+//
+// See also TransitiveReduction.
+func (g Directed) MinimumEquivalentGraph() Directed {
+	a := g.AdjacencyList
+	scc, cd := g.Condensation()
+	cond := make([]NI, len(a)) // mapping from g node to cd node
+	r := make(AdjacencyList, len(a))
+	for cn, c := range scc {
+		for i, n := range c {
+			cond[n] = NI(cn)
+			if len(c) > 1 {
+				r[n] = append(r[n], c[(i+1)%len(c)])
+			}
+		}
+	}
+	// components are in reverse topological order
+	ordering := make([]NI, len(cd))
+	for i := range ordering {
+		ordering[i] = NI(len(cd) - 1 - i)
+	}
+	keep := reduceDAG(cd, ordering)
+	for fr, to := range a {
+		cf := cond[fr]
+		for _, to := range to {
+			if ct := cond[to]; keep[cf].Bit(int(ct)) == 1 {
+				keep[cf].SetBit(int(ct), 0) // one arc per component pair
+				r[fr] = append(r[fr], to)
+			}
+		}
+	}
+	return Directed{r}
+}
+
 // SpanTree builds a tree spanning nodes reachable from the given root.
 //
 // The component is spanned by breadth-first search from root.
@@ -276,6 +459,85 @@ func (g Directed) Undirected() Undirected {
 	return Undirected{c}
 }
 
+// TransitiveReduction returns the transitive reduction of a directed
+// acyclic graph.
+//
+// The transitive reduction is the subgraph of g with the fewest arcs having
+// the same reachability as g.  That is, an arc fr->to of g is removed if
+// there is another path from fr to to.  Parallel arcs are also reduced to a
+// single arc.  Arcs retained are in the same order as in g.
+//
+// If g is cyclic, the result r is a zero value Directed and cycle is the
+// path of a found cycle.  See MinimumEquivalentGraph for cyclic graphs.
+func (g Directed) TransitiveReduction() (r Directed, cycle []NI) {
+	ordering, cycle := g.Topological()
+	if cycle != nil {
+		return Directed{}, cycle
+	}
+	a := g.AdjacencyList
+	keep := reduceDAG(a, ordering)
+	ra := make(AdjacencyList, len(a))
+	for fr, to := range a {
+		for _, to := range to {
+			if keep[fr].Bit(int(to)) == 1 {
+				keep[fr].SetBit(int(to), 0) // drop parallel arcs
+				ra[fr] = append(ra[fr], to)
+			}
+		}
+	}
+	return Directed{ra}, nil
+}
+
+// reduceDAG computes the arcs of the transitive reduction of DAG a, given
+// a topological ordering.
+//
+// The result is an adjacency matrix of arcs to keep.
+func reduceDAG(a AdjacencyList, ordering []NI) []bits.Bits {
+	pos := make([]int, len(a))
+	for i, n := range ordering {
+		pos[n] = i
+	}
+	reach := make([]bits.Bits, len(a)) // nodes reachable by non-empty paths
+	keep := make([]bits.Bits, len(a))
+	var to []NI
+	for i := len(ordering) - 1; i >= 0; i-- {
+		n := ordering[i]
+		r := bits.New(len(a))
+		k := bits.New(len(a))
+		// visit successors nearest first.  a successor already reachable
+		// through a nearer one is redundant.
+		to = append(to[:0], a[n]...)
+		sort.Slice(to, func(i, j int) bool { return pos[to[i]] < pos[to[j]] })
+		for _, s := range to {
+			if r.Bit(int(s)) == 0 {
+				k.SetBit(int(s), 1)
+				r.SetBit(int(s), 1)
+				r.Or(r, reach[s])
+			}
+		}
+		reach[n] = r
+		keep[n] = k
+	}
+	return keep
+}
+
+// niHeap implements container/heap for TopologicalPriority.
+type niHeap struct {
+	nodes []NI
+	less  func(a, b NI) bool
+}
+
+func (h niHeap) Len() int            { return len(h.nodes) }
+func (h niHeap) Less(i, j int) bool  { return h.less(h.nodes[i], h.nodes[j]) }
+func (h niHeap) Swap(i, j int)       { h.nodes[i], h.nodes[j] = h.nodes[j], h.nodes[i] }
+func (h *niHeap) Push(x interface{}) { h.nodes = append(h.nodes, x.(NI)) }
+func (h *niHeap) Pop() interface{} {
+	last := len(h.nodes) - 1
+	n := h.nodes[last]
+	h.nodes = h.nodes[:last]
+	return n
+}
+
 // Transpose constructs a new adjacency list with all arcs reversed.
 //
 // For every arc from->to of g, the result will have an arc to->from.
@@ -998,9 +1260,40 @@ type Dominators struct {
 	Immediate []NI
 	from      interface { // either Directed or LabeledDirected
 		domFrontiers(Dominators) DominanceFrontiers
+		domPreds() AdjacencyList
 	}
 }
 
+// DomAlgorithm selects an algorithm for computing dominators.
+//
+// See DominatorsAlg and PostDominatorsAlg.
+type DomAlgorithm int
+
+const (
+	// DomIterative is the iterative algorithm of Cooper, Harvey, and
+	// Kennedy, as used by Dominators and Doms.  It is simple and fast on
+	// shallow graphs.
+	DomIterative DomAlgorithm = iota
+	// DomSemiNCA is the SEMI-NCA variant of Lengauer-Tarjan.  It takes
+	// near-linear time and is preferred for large or deep graphs.
+	DomSemiNCA
+)
+
+// DominatorTree constructs the dominator tree as a directed graph.
+//
+// The result has an arc from the immediate dominator of each node to the
+// node.  The root, the start node of the dominator computation, and nodes
+// not in the reachable subgraph have no in-arcs.
+func (d Dominators) DominatorTree() Directed {
+	t := make(AdjacencyList, len(d.Immediate))
+	for n, im := range d.Immediate {
+		if im >= 0 && im != NI(n) {
+			t[im] = append(t[im], NI(n))
+		}
+	}
+	return Directed{t}
+}
+
 // Frontiers constructs the dominator frontier for each node.
 //
 // The frontier for a node is a set of nodes, represented as a map.  The
@@ -1012,6 +1305,128 @@ func (d Dominators) Frontiers() DominanceFrontiers {
 	return d.from.domFrontiers(d)
 }
 
+// Loops finds natural loops and returns the loop-nesting forest.
+//
+// A natural loop is identified by a back arc n->h where h dominates n.
+// Node h is the loop header.  The loop is h and all nodes that can reach a
+// back arc source n without passing through h.  Loops with the same header
+// are merged.  The loops of a graph are either disjoint or nested.
+//
+// Return value headers lists loop headers in ascending order.  The FromList
+// f represents the nesting.  For each node n, f.Paths[n].From is the header
+// of the innermost loop containing n, or -1 if n is in no loop.  For a
+// header h, the innermost loop containing h is the loop enclosing the loop
+// of h.  Other members of the FromList are left as zero values.
+//
+// Loops are relative to the graph for which dominators were computed.  For
+// postdominators, these are loops of the transpose, which has the same
+// loops with arcs reversed.
+//
+// Only reducible loops, those entered through their header, are found.
+// Cycles entered at multiple nodes are not natural loops and are not
+// reported.
+//
+// Ref: "Identifying Loops In Almost Linear Time", G. Ramalingam, ACM
+// Transactions on Programming Languages and Systems (1999).
+func (d Dominators) Loops() (f FromList, headers []NI) {
+	im := d.Immediate
+	pred := d.from.domPreds()
+	paths := make([]PathEnd, len(im))
+	for n := range paths {
+		paths[n].From = -1
+	}
+	// preorder intervals of the dominator tree for constant time
+	// dominance tests.
+	t := d.DominatorTree().AdjacencyList
+	pre := make([]int, len(im))
+	last := make([]int, len(im))
+	var order []NI
+	var stack []NI
+	for n, dn := range im {
+		if dn != NI(n) {
+			continue // not the root
+		}
+		stack = append(stack[:0], NI(n))
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			pre[n] = len(order)
+			order = append(order, n)
+			stack = append(stack, t[n]...)
+		}
+	}
+	for i := len(order) - 1; i >= 0; i-- {
+		n := order[i]
+		last[n] = pre[n]
+		for _, c := range t[n] {
+			if last[c] > last[n] {
+				last[n] = last[c]
+			}
+		}
+	}
+	dominates := func(h, n NI) bool {
+		return pre[h] <= pre[n] && pre[n] <= last[h]
+	}
+	// union-find, each processed loop collapsed to its header
+	rep := make([]NI, len(im))
+	for n := range rep {
+		rep[n] = NI(n)
+	}
+	find := func(n NI) NI {
+		r := n
+		for rep[r] != r {
+			r = rep[r]
+		}
+		for rep[n] != r {
+			rep[n], n = r, rep[n]
+		}
+		return r
+	}
+	mark := make([]NI, len(im)) // header of search that visited node
+	for n := range mark {
+		mark[n] = -1
+	}
+	isHeader := make([]bool, len(im))
+	var work []NI
+	// inner loops first, by reverse preorder of the dominator tree
+	for i := len(order) - 1; i >= 0; i-- {
+		h := order[i]
+		work = work[:0]
+		for _, n := range pred[h] {
+			if im[n] >= 0 && dominates(h, n) {
+				isHeader[h] = true
+				if r := find(n); r != h {
+					work = append(work, r)
+				}
+			}
+		}
+		for len(work) > 0 {
+			x := work[len(work)-1]
+			work = work[:len(work)-1]
+			if mark[x] == h {
+				continue
+			}
+			mark[x] = h
+			paths[x].From = h
+			rep[x] = h
+			for _, y := range pred[x] {
+				if im[y] < 0 {
+					continue
+				}
+				if r := find(y); r != h && mark[r] != h {
+					work = append(work, r)
+				}
+			}
+		}
+	}
+	for n, h := range isHeader {
+		if h {
+			headers = append(headers, NI(n))
+		}
+	}
+	return FromList{Paths: paths}, headers
+}
+
 // Set constructs the dominator set for a given node.
 //
 // The dominator set for a node always includes the node itself as the first
@@ -1033,6 +1448,16 @@ func (d Dominators) Set(n NI) []NI {
 	}
 }
 
+// called from Dominators.Loops via interface
+func (from Directed) domPreds() AdjacencyList {
+	return from.AdjacencyList
+}
+
+// called from Dominators.Loops via interface
+func (from LabeledDirected) domPreds() AdjacencyList {
+	return from.Unlabeled().AdjacencyList
+}
+
 // starting at the node on the top of the stack, follow arcs until stuck.
 // mark nodes visited, push nodes on stack, remove arcs from g.
 func (e *eulerian) push() {
@@ -4,6 +4,7 @@
 package graph
 
 import (
+	"container/heap"
 	"errors"
 	"fmt"
 
@@ -142,6 +143,23 @@ func (g LabeledDirected) Dominators(start NI) Dominators {
 	return g.Doms(tr, post[l:])
 }
 
+// DominatorsAlg computes the immediate dominator for each node reachable
+// from start, using the algorithm selected by alg.
+//
+// With DomIterative the result is that of Dominators.  With DomSemiNCA
+// the algorithm is the SEMI-NCA variant of Lengauer-Tarjan, which takes
+// near-linear time regardless of the depth of the graph.  Results of the
+// two algorithms are identical.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledDirected) DominatorsAlg(start NI, alg DomAlgorithm) Dominators {
+	if alg != DomSemiNCA {
+		return g.Dominators(start)
+	}
+	tr, _ := g.Transpose()
+	return Dominators{g.semiNCA(tr, start), tr}
+}
+
 // Doms computes either immediate dominators or postdominators.
 //
 // The slice returned as Dominators.Immediate will have the length of
@@ -243,6 +261,111 @@ func (g LabeledDirected) PostDominators(end NI) Dominators {
 	return tr.Doms(g, post[l:])
 }
 
+// PostDominatorsAlg computes the immediate postdominator for each node that
+// can reach node end, using the algorithm selected by alg.
+//
+// See DominatorsAlg and PostDominators.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledDirected) PostDominatorsAlg(end NI, alg DomAlgorithm) Dominators {
+	if alg != DomSemiNCA {
+		return g.PostDominators(end)
+	}
+	tr, _ := g.Transpose()
+	return Dominators{tr.semiNCA(g, end), g}
+}
+
+// semiNCA computes immediate dominators by the SEMI-NCA algorithm.
+//
+// Argument tr must be the transpose of g.  The depth-first search and
+// path compression are iterative so that deep graphs do not recurse deeply.
+//
+// Ref: "Finding Dominators in Practice", Loukas Georgiadis, Robert E.
+// Tarjan, and Renato F. Werneck, J. Graph Algorithms Appl. (2006).
+func (g LabeledDirected) semiNCA(tr LabeledDirected, start NI) []NI {
+	a := g.LabeledAdjacencyList
+	dom := make([]NI, len(a))
+	dfn := make([]int32, len(a)) // preorder number, -1 if unreached
+	for n := range dfn {
+		dom[n] = -1
+		dfn[n] = -1
+	}
+	// depth-first preorder, with parents in preorder numbers
+	var vertex []NI
+	var parent []int32
+	type frame struct {
+		n NI
+		x int
+	}
+	dfn[start] = 0
+	vertex = append(vertex, start)
+	parent = append(parent, -1)
+	stack := []frame{{start, 0}}
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.x == len(a[f.n]) {
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		to := a[f.n][f.x].To
+		f.x++
+		if dfn[to] < 0 {
+			dfn[to] = int32(len(vertex))
+			vertex = append(vertex, to)
+			parent = append(parent, dfn[f.n])
+			stack = append(stack, frame{to, 0})
+		}
+	}
+	// semidominators, by preorder number
+	semi := make([]int32, len(vertex))
+	label := make([]int32, len(vertex))
+	ancestor := make([]int32, len(vertex))
+	for i := range semi {
+		semi[i] = int32(i)
+		label[i] = int32(i)
+		ancestor[i] = -1
+	}
+	var path []int32
+	eval := func(v int32) int32 {
+		if ancestor[v] < 0 {
+			return v
+		}
+		path = path[:0]
+		for u := v; ancestor[ancestor[u]] >= 0; u = ancestor[u] {
+			path = append(path, u)
+		}
+		for i := len(path) - 1; i >= 0; i-- {
+			u := path[i]
+			au := ancestor[u]
+			if semi[label[au]] < semi[label[u]] {
+				label[u] = label[au]
+			}
+			ancestor[u] = ancestor[au]
+		}
+		return label[v]
+	}
+	for i := int32(len(vertex) - 1); i > 0; i-- {
+		for _, fr := range tr.LabeledAdjacencyList[vertex[i]] {
+			if v := dfn[fr.To]; v >= 0 {
+				if s := semi[eval(v)]; s < semi[i] {
+					semi[i] = s
+				}
+			}
+		}
+		ancestor[i] = parent[i]
+	}
+	// immediate dominators as nearest common ancestors
+	idom := parent
+	for i := int32(1); i < int32(len(vertex)); i++ {
+		for idom[i] > semi[i] {
+			idom[i] = idom[idom[i]]
+		}
+		dom[vertex[i]] = vertex[idom[i]]
+	}
+	dom[start] = start
+	return dom
+}
+
 // called from Dominators.Frontier via interface
 func (from LabeledDirected) domFrontiers(d Dominators) DominanceFrontiers {
 	im := d.Immediate
@@ -1030,6 +1153,111 @@ func (g LabeledDirected) TopologicalKahn(tr Directed) (ordering, cycle []NI) {
 	return L, nil
 }
 
+// TopologicalPriority computes a deterministic topological ordering of a
+// directed acyclic graph.
+//
+// At each step, the node chosen from those with no remaining in-arcs is the
+// least by function less.  If less is nil, node numbers are compared and the
+// result is the lexicographically smallest topological ordering.
+//
+// For an acyclic graph, return value ordering is a permutation of node numbers
+// in topologically sorted order and cycle will be nil.  If the graph is found
+// to be cyclic, ordering will be nil and cycle will be the path of a found
+// cycle, as returned by Topological.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledDirected) TopologicalPriority(less func(a, b NI) bool) (ordering, cycle []NI) {
+	a := g.LabeledAdjacencyList
+	if less == nil {
+		less = func(x, y NI) bool { return x < y }
+	}
+	rem := make([]int, len(a))
+	for _, to := range a {
+		for _, to := range to {
+			rem[to.To]++
+		}
+	}
+	q := &niHeap{less: less}
+	for n, r := range rem {
+		if r == 0 {
+			q.nodes = append(q.nodes, NI(n))
+		}
+	}
+	heap.Init(q)
+	ordering = make([]NI, 0, len(a))
+	for q.Len() > 0 {
+		n := heap.Pop(q).(NI)
+		ordering = append(ordering, n)
+		for _, to := range a[n] {
+			if rem[to.To]--; rem[to.To] == 0 {
+				heap.Push(q, to.To)
+			}
+		}
+	}
+	if len(ordering) < len(a) {
+		_, cycle = g.Topological()
+		return nil, cycle
+	}
+	return ordering, nil
+}
+
+// TopologicalAll enumerates all topological orderings of a directed acyclic
+// graph.
+//
+// Orderings are emitted in lexicographic order.  The emit function is called
+// for each ordering.  The backing slice of the ordering is reused across emit
+// calls.  If you need to retain the ordering you must copy it.  Emit must
+// return true to continue or false to stop.
+//
+// The number of orderings can be exponential in the order of g.
+//
+// If the graph is cyclic, nothing is emitted and cycle will be the path of
+// a found cycle, as returned by Topological.  Otherwise cycle is nil.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledDirected) TopologicalAll(emit func([]NI) bool) (cycle []NI) {
+	if _, cycle = g.Topological(); cycle != nil {
+		return
+	}
+	a := g.LabeledAdjacencyList
+	rem := make([]int, len(a))
+	for _, to := range a {
+		for _, to := range to {
+			rem[to.To]++
+		}
+	}
+	used := bits.New(len(a))
+	ordering := make([]NI, 0, len(a))
+	var f func() bool
+	f = func() bool {
+		if len(ordering) == len(a) {
+			return emit(ordering)
+		}
+		for n := range a {
+			if rem[n] > 0 || used.Bit(n) == 1 {
+				continue
+			}
+			used.SetBit(n, 1)
+			ordering = append(ordering, NI(n))
+			for _, to := range a[n] {
+				rem[to.To]--
+			}
+			ok := f()
+			for _, to := range a[n] {
+				rem[to.To]++
+			}
+			ordering = ordering[:len(ordering)-1]
+			used.SetBit(n, 0)
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+	f()
+	return nil
+}
+
 // TopologicalSubgraph computes a topological ordering of a subgraph of a
 // directed acyclic graph.
 //
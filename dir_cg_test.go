@@ -134,6 +134,27 @@ func ExampleLabeledDirected_Dominators() {
 	// [0 0 1 1 1 3 -1]
 }
 
+func ExampleLabeledDirected_DominatorsAlg() {
+	//   0   6
+	//   |   |
+	//   1   |
+	//  / \  |
+	// 2   3 |
+	//  \ / \|
+	//   4   5
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1}},
+		1: {{To: 2}, {To: 3}},
+		2: {{To: 4}},
+		3: {{To: 4}, {To: 5}},
+		6: {{To: 5}},
+	}}
+	d := g.DominatorsAlg(0, graph.DomSemiNCA)
+	fmt.Println(d.Immediate)
+	// Output:
+	// [0 0 1 1 1 3 -1]
+}
+
 func ExampleLabeledDirected_Doms() {
 	//   0   6
 	//   |   |
@@ -525,6 +546,28 @@ func ExampleLabeledDirected_PostDominators() {
 	// [0 0 1 1 1 3 -1]
 }
 
+func ExampleLabeledDirected_PostDominatorsAlg() {
+	//   4   5
+	//  / \ /|
+	// 2   3 |
+	//  \ /  |
+	//   1   |
+	//   |   |
+	//   0   6
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		4: {{To: 2}, {To: 3}},
+		5: {{To: 3}, {To: 6}},
+		2: {{To: 1}},
+		3: {{To: 1}},
+		1: {{To: 0}},
+		6: {},
+	}}
+	d := g.PostDominatorsAlg(0, graph.DomSemiNCA)
+	fmt.Println(d.Immediate)
+	// Output:
+	// [0 0 1 1 1 3 -1]
+}
+
 func ExampleLabeledDirected_PageRank() {
 	//     0<-\
 	//    / \ |
@@ -635,6 +678,31 @@ func ExampleLabeledDirected_Topological() {
 	// [] [1 2 3]
 }
 
+func ExampleLabeledDirected_TopologicalAll() {
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		1: {{To: 2}},
+		3: {{To: 1}, {To: 2}},
+		4: {{To: 3}, {To: 2}},
+	}}
+	fmt.Println(g.TopologicalAll(func(o []graph.NI) bool {
+		fmt.Println(o)
+		return true
+	}))
+	g.LabeledAdjacencyList[2] = []graph.Half{{To: 3}}
+	fmt.Println(g.TopologicalAll(func(o []graph.NI) bool {
+		fmt.Println(o)
+		return true
+	}))
+	// Output:
+	// [0 4 3 1 2]
+	// [4 0 3 1 2]
+	// [4 3 0 1 2]
+	// [4 3 1 0 2]
+	// [4 3 1 2 0]
+	// []
+	// [1 2 3]
+}
+
 func ExampleLabeledDirected_TopologicalKahn() {
 	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
 		1: {{To: 2}},
@@ -652,6 +720,24 @@ func ExampleLabeledDirected_TopologicalKahn() {
 	// [] [1 2 3]
 }
 
+func ExampleLabeledDirected_TopologicalPriority() {
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		1: {{To: 2}},
+		3: {{To: 1}, {To: 2}},
+		4: {{To: 3}, {To: 2}},
+	}}
+	fmt.Println(g.TopologicalPriority(nil))
+	fmt.Println(g.TopologicalPriority(func(a, b graph.NI) bool {
+		return a > b
+	}))
+	g.LabeledAdjacencyList[2] = []graph.Half{{To: 3}}
+	fmt.Println(g.TopologicalPriority(nil))
+	// Output:
+	// [0 4 3 1 2] []
+	// [4 3 1 2 0] []
+	// [] [1 2 3]
+}
+
 func ExampleLabeledDirected_TopologicalSubgraph() {
 	// arcs directected down unless otherwise indicated
 	// 0       1<-\
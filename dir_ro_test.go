@@ -138,6 +138,27 @@ func ExampleDirected_Dominators() {
 	// [0 0 1 1 1 3 -1]
 }
 
+func ExampleDirected_DominatorsAlg() {
+	//   0   6
+	//   |   |
+	//   1   |
+	//  / \  |
+	// 2   3 |
+	//  \ / \|
+	//   4   5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2, 3},
+		2: {4},
+		3: {4, 5},
+		6: {5},
+	}}
+	d := g.DominatorsAlg(0, graph.DomSemiNCA)
+	fmt.Println(d.Immediate)
+	// Output:
+	// [0 0 1 1 1 3 -1]
+}
+
 func ExampleDirected_Doms() {
 	//   0   6
 	//   |   |
@@ -544,6 +565,28 @@ func ExampleDirected_PostDominators() {
 	// [0 0 1 1 1 3 -1]
 }
 
+func ExampleDirected_PostDominatorsAlg() {
+	//   4   5
+	//  / \ /|
+	// 2   3 |
+	//  \ /  |
+	//   1   |
+	//   |   |
+	//   0   6
+	g := graph.Directed{graph.AdjacencyList{
+		4: {2, 3},
+		5: {3, 6},
+		2: {1},
+		3: {1},
+		1: {0},
+		6: {},
+	}}
+	d := g.PostDominatorsAlg(0, graph.DomSemiNCA)
+	fmt.Println(d.Immediate)
+	// Output:
+	// [0 0 1 1 1 3 -1]
+}
+
 func ExampleDirected_PageRank() {
 	//     0<-\
 	//    / \ |
@@ -654,6 +697,31 @@ func ExampleDirected_Topological() {
 	// [] [1 2 3]
 }
 
+func ExampleDirected_TopologicalAll() {
+	g := graph.Directed{graph.AdjacencyList{
+		1: {2},
+		3: {1, 2},
+		4: {3, 2},
+	}}
+	fmt.Println(g.TopologicalAll(func(o []graph.NI) bool {
+		fmt.Println(o)
+		return true
+	}))
+	g.AdjacencyList[2] = []graph.NI{3}
+	fmt.Println(g.TopologicalAll(func(o []graph.NI) bool {
+		fmt.Println(o)
+		return true
+	}))
+	// Output:
+	// [0 4 3 1 2]
+	// [4 0 3 1 2]
+	// [4 3 0 1 2]
+	// [4 3 1 0 2]
+	// [4 3 1 2 0]
+	// []
+	// [1 2 3]
+}
+
 func ExampleDirected_TopologicalKahn() {
 	g := graph.Directed{graph.AdjacencyList{
 		1: {2},
@@ -671,6 +739,24 @@ func ExampleDirected_TopologicalKahn() {
 	// [] [1 2 3]
 }
 
+func ExampleDirected_TopologicalPriority() {
+	g := graph.Directed{graph.AdjacencyList{
+		1: {2},
+		3: {1, 2},
+		4: {3, 2},
+	}}
+	fmt.Println(g.TopologicalPriority(nil))
+	fmt.Println(g.TopologicalPriority(func(a, b graph.NI) bool {
+		return a > b
+	}))
+	g.AdjacencyList[2] = []graph.NI{3}
+	fmt.Println(g.TopologicalPriority(nil))
+	// Output:
+	// [0 4 3 1 2] []
+	// [4 3 1 2 0] []
+	// [] [1 2 3]
+}
+
 func ExampleDirected_TopologicalSubgraph() {
 	// arcs directected down unless otherwise indicated
 	// 0       1<-\
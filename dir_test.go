@@ -6,6 +6,7 @@ package graph_test
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"reflect"
 	"testing"
 
@@ -91,6 +92,25 @@ func TestCycles(t *testing.T) {
 	}
 }
 
+func ExampleDirected_CoffmanGraham() {
+	// arcs directed down
+	//   0   1     6
+	//    \ / \
+	//     2   3
+	//      \ / \
+	//       4   5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {2},
+		1: {2, 3},
+		2: {4},
+		3: {4, 5},
+		6: {},
+	}}
+	fmt.Println(g.CoffmanGraham(2))
+	// Output:
+	// [[0] [1 6] [2 3] [4 5]] []
+}
+
 func ExampleDirected_DAGMaxLenPath() {
 	// arcs directed right:
 	//      /---\
@@ -240,6 +260,326 @@ func ExampleDirected_FromList_rootLoop() {
 	// 2    0
 }
 
+func ExampleDirected_Layers() {
+	// arcs directed down
+	//   0   1     6
+	//    \ / \
+	//     2   3
+	//      \ / \
+	//       4   5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {2},
+		1: {2, 3},
+		2: {4},
+		3: {4, 5},
+		6: {},
+	}}
+	fmt.Println(g.Layers())
+	g.AdjacencyList[5] = []graph.NI{1}
+	fmt.Println(g.Layers())
+	// Output:
+	// [[0 1 6] [2 3] [4 5]] []
+	// [] [1 3 5]
+}
+
+func TestLayers(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 300; i++ {
+		// random DAG, arcs consistent with a random permutation
+		n := 1 + r.Intn(9)
+		pm := r.Perm(n)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		pred := make([]int, n) // bits of predecessor nodes
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if r.Intn(3) == 0 {
+					g.AdjacencyList[pm[i]] = append(g.AdjacencyList[pm[i]], graph.NI(pm[j]))
+					pred[pm[j]] |= 1 << uint(pm[i])
+				}
+			}
+		}
+		// check layers are a partition with arcs going forward
+		check := func(layers [][]graph.NI, w int) []int {
+			in := make([]int, n)
+			for i := range in {
+				in[i] = -1
+			}
+			for l, ly := range layers {
+				if len(ly) == 0 || w > 0 && len(ly) > w {
+					t.Fatalf("layer width %d: %v", w, layers)
+				}
+				for _, v := range ly {
+					if in[v] >= 0 {
+						t.Fatal("node in two layers")
+					}
+					in[v] = l
+				}
+			}
+			for fr, to := range g.AdjacencyList {
+				if in[fr] < 0 {
+					t.Fatal("node not in a layer")
+				}
+				for _, to := range to {
+					if in[fr] >= in[to] {
+						t.Fatalf("arc %d->%d in layers %v", fr, to, layers)
+					}
+				}
+			}
+			return in
+		}
+		layers, cycle := g.Layers()
+		if cycle != nil {
+			t.Fatal("cycle in DAG")
+		}
+		in := check(layers, 0)
+		for v, l := range in {
+			if l == 0 {
+				if pred[v] != 0 {
+					t.Fatal("node with predecessor in layer 0")
+				}
+				continue
+			}
+			ok := false
+			for u := 0; u < n; u++ {
+				if pred[v]&(1<<uint(u)) != 0 && in[u] == l-1 {
+					ok = true
+				}
+			}
+			if !ok {
+				t.Fatal("node not in earliest layer")
+			}
+		}
+		for w := 1; w <= 3; w++ {
+			layers, cycle := g.CoffmanGraham(w)
+			if cycle != nil {
+				t.Fatal("cycle in DAG")
+			}
+			check(layers, w)
+			if w != 2 {
+				continue
+			}
+			// brute force minimum number of steps of two tasks each
+			all := 1<<uint(n) - 1
+			dist := map[int]int{0: 0}
+			q := []int{0}
+			for len(q) > 0 && q[0] != all {
+				done := q[0]
+				q = q[1:]
+				var av []int
+				for v := 0; v < n; v++ {
+					if done&(1<<uint(v)) == 0 && pred[v]&^done == 0 {
+						av = append(av, v)
+					}
+				}
+				for i, u := range av {
+					next := []int{done | 1<<uint(u)}
+					for _, v := range av[i+1:] {
+						next = append(next, done|1<<uint(u)|1<<uint(v))
+					}
+					for _, d := range next {
+						if _, ok := dist[d]; !ok {
+							dist[d] = dist[done] + 1
+							q = append(q, d)
+						}
+					}
+				}
+			}
+			if len(layers) != dist[all] {
+				t.Fatalf("%d layers, want %d", len(layers), dist[all])
+			}
+		}
+	}
+	g := graph.Directed{graph.AdjacencyList{0: {1}, 1: {0}}}
+	if _, cycle := g.CoffmanGraham(2); cycle == nil {
+		t.Fatal("no cycle")
+	}
+}
+
+func ExampleDirected_MinimumEquivalentGraph() {
+	// 0-->1-->2-->3
+	//  ^  |  ^    |
+	//   \ v /     v
+	//     4 ----->5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2, 4},
+		2: {3},
+		3: {5},
+		4: {0, 2, 5},
+		5: {},
+	}}
+	r := g.MinimumEquivalentGraph()
+	for n, to := range r.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// 0 [1]
+	// 1 [4 2]
+	// 2 [3]
+	// 3 [5]
+	// 4 [0]
+	// 5 []
+}
+
+func TestMinimumEquivalentGraph(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(10)
+		g := graph.GnmDirected(n, r.Intn(n*(n-1)/2+1), r)
+		tc := g.TransitiveClosure()
+		m := g.MinimumEquivalentGraph()
+		if !reflect.DeepEqual(m.TransitiveClosure(), tc) {
+			t.Fatal("reachability differs")
+		}
+		// arc count from the condensation
+		scc, cd := g.Condensation()
+		want := graph.Directed{cd}
+		cr := 0
+		for _, c := range scc {
+			if len(c) > 1 {
+				cr += len(c)
+			}
+		}
+		rcd, _ := want.TransitiveReduction()
+		if got := m.ArcSize(); got != cr+rcd.ArcSize() {
+			t.Fatalf("%d arcs, want %d", got, cr+rcd.ArcSize())
+		}
+	}
+}
+
+func ExampleDirected_TransitiveReduction() {
+	// arcs directed down:
+	//    0
+	//   /|\
+	//  1 | 2
+	//   \|/
+	//    3
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2, 3},
+		1: {3},
+		2: {3},
+		3: {},
+	}}
+	r, _ := g.TransitiveReduction()
+	for n, to := range r.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// 0 [1 2]
+	// 1 [3]
+	// 2 [3]
+	// 3 []
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		// random DAG, arcs from lower to higher node numbers
+		n := 1 + r.Intn(10)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for fr := 0; fr < n; fr++ {
+			for to := fr + 1; to < n; to++ {
+				if r.Intn(2) == 0 {
+					g.AdjacencyList[fr] = append(g.AdjacencyList[fr], graph.NI(to))
+				}
+			}
+		}
+		tc := g.TransitiveClosure()
+		tr, cycle := g.TransitiveReduction()
+		if cycle != nil {
+			t.Fatal("cycle in DAG")
+		}
+		if !reflect.DeepEqual(tr.TransitiveClosure(), tc) {
+			t.Fatal("reachability differs")
+		}
+		// each arc is needed
+		for fr, arcs := range tr.AdjacencyList {
+			for x, to := range arcs {
+				if ok, _ := g.HasArc(graph.NI(fr), to); !ok {
+					t.Fatal("arc not in g")
+				}
+				c, _ := tr.Copy()
+				c.AdjacencyList[fr] = append(append([]graph.NI{}, arcs[:x]...),
+					arcs[x+1:]...)
+				if reflect.DeepEqual(c.TransitiveClosure(), tc) {
+					t.Fatal("redundant arc", fr, to)
+				}
+			}
+		}
+	}
+	g := graph.Directed{graph.AdjacencyList{0: {1}, 1: {0}}}
+	if _, cycle := g.TransitiveReduction(); cycle == nil {
+		t.Fatal("no cycle")
+	}
+}
+
+func TestTopologicalAll(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		// random DAG, arcs consistent with a random permutation
+		n := 1 + r.Intn(7)
+		pm := r.Perm(n)
+		g := graph.Directed{make(graph.AdjacencyList, n)}
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				if r.Intn(2) == 0 {
+					g.AdjacencyList[pm[i]] = append(g.AdjacencyList[pm[i]], graph.NI(pm[j]))
+				}
+			}
+		}
+		// brute force, permutations generated in lexicographic order
+		var want []string
+		p := make([]graph.NI, 0, n)
+		used := make([]bool, n)
+		var perm func()
+		perm = func() {
+			if len(p) == n {
+				pos := make([]int, n)
+				for i, v := range p {
+					pos[v] = i
+				}
+				for fr, to := range g.AdjacencyList {
+					for _, to := range to {
+						if pos[fr] > pos[to] {
+							return
+						}
+					}
+				}
+				want = append(want, fmt.Sprint(p))
+				return
+			}
+			for v := 0; v < n; v++ {
+				if !used[v] {
+					used[v] = true
+					p = append(p, graph.NI(v))
+					perm()
+					p = p[:len(p)-1]
+					used[v] = false
+				}
+			}
+		}
+		perm()
+		var got []string
+		if cycle := g.TopologicalAll(func(o []graph.NI) bool {
+			got = append(got, fmt.Sprint(o))
+			return true
+		}); cycle != nil {
+			t.Fatal("cycle in DAG")
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("got %v want %v", got, want)
+		}
+		o, _ := g.TopologicalPriority(nil)
+		if s := fmt.Sprint(o); s != want[0] {
+			t.Fatalf("priority %s, want %s", s, want[0])
+		}
+		o, _ = g.TopologicalPriority(func(a, b graph.NI) bool { return a > b })
+		if s := fmt.Sprint(o); s != want[len(want)-1] {
+			t.Fatalf("reverse priority %s, want %s", s, want[len(want)-1])
+		}
+	}
+}
+
 func ExampleDirected_Transpose() {
 	g := graph.Directed{graph.AdjacencyList{
 		2: {0, 1},
@@ -395,6 +735,35 @@ func ExampleDominanceFrontiers_Frontier_labeled() {
 	// map[2:{}]
 }
 
+func ExampleDominators_DominatorTree() {
+	//   0
+	//   |
+	//   1
+	//  / \
+	// 2   3
+	//  \ / \
+	//   4   5   6
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2, 3},
+		2: {4},
+		3: {4, 5},
+		6: {},
+	}}
+	t := g.Dominators(0).DominatorTree()
+	for n, to := range t.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// 0 [1]
+	// 1 [2 3 4]
+	// 2 []
+	// 3 [5]
+	// 4 []
+	// 5 []
+	// 6 []
+}
+
 func ExampleDominators_Frontiers() {
 	//   0
 	//   |
@@ -431,6 +800,41 @@ func ExampleDominators_Frontiers() {
 	// 6: nil
 }
 
+func ExampleDominators_Loops() {
+	//   0
+	//   |
+	//   1<----\
+	//   |     |
+	//   2<-\  |
+	//   |  |  |
+	//   3--/  |
+	//   |     |
+	//   4-----/
+	//   |
+	//   5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3},
+		3: {2, 4},
+		4: {1, 5},
+		5: {},
+	}}
+	f, headers := g.Dominators(0).Loops()
+	fmt.Println("headers:", headers)
+	for n, e := range f.Paths {
+		fmt.Println(n, e.From)
+	}
+	// Output:
+	// headers: [1 2]
+	// 0 -1
+	// 1 -1
+	// 2 1
+	// 3 2
+	// 4 1
+	// 5 -1
+}
+
 func ExampleDominators_Set() {
 	//   0
 	//   |
@@ -460,6 +864,110 @@ func ExampleDominators_Set() {
 	// 6 []
 }
 
+func TestDominatorsAlg(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 300; i++ {
+		n := 1 + r.Intn(15)
+		g := graph.GnmDirected(n, r.Intn(2*n+1), r)
+		start := graph.NI(r.Intn(n))
+		if a, b := g.Dominators(start), g.DominatorsAlg(start, graph.DomSemiNCA); !reflect.DeepEqual(a.Immediate, b.Immediate) {
+			t.Fatalf("%v: iterative %v, SEMI-NCA %v", g, a.Immediate, b.Immediate)
+		}
+		if a, b := g.PostDominators(start), g.PostDominatorsAlg(start, graph.DomSemiNCA); !reflect.DeepEqual(a.Immediate, b.Immediate) {
+			t.Fatalf("%v: iterative %v, SEMI-NCA %v", g, a.Immediate, b.Immediate)
+		}
+		lg := graph.LabeledDirected{make(graph.LabeledAdjacencyList, n)}
+		for fr, to := range g.AdjacencyList {
+			for _, to := range to {
+				lg.LabeledAdjacencyList[fr] = append(lg.LabeledAdjacencyList[fr], graph.Half{To: to})
+			}
+		}
+		if a, b := lg.Dominators(start), lg.DominatorsAlg(start, graph.DomSemiNCA); !reflect.DeepEqual(a.Immediate, b.Immediate) {
+			t.Fatalf("%v: iterative %v, SEMI-NCA %v", g, a.Immediate, b.Immediate)
+		}
+	}
+	// a deep graph
+	n := 100000
+	a := make(graph.AdjacencyList, n)
+	for i := 0; i < n-1; i++ {
+		a[i] = []graph.NI{graph.NI(i + 1), 0}
+	}
+	d := graph.Directed{a}.DominatorsAlg(0, graph.DomSemiNCA)
+	if d.Immediate[n-1] != graph.NI(n-2) {
+		t.Fatal("deep graph")
+	}
+}
+
+func TestLoops(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 300; i++ {
+		n := 1 + r.Intn(12)
+		g := graph.GnmDirected(n, r.Intn(2*n+1), r)
+		tr, _ := g.Transpose()
+		d := g.Dominators(0)
+		im := d.Immediate
+		dominates := func(h, n graph.NI) bool {
+			for _, s := range d.Set(n) {
+				if s == h {
+					return true
+				}
+			}
+			return false
+		}
+		// brute force loop bodies
+		body := map[graph.NI]map[graph.NI]bool{}
+		var headers []graph.NI
+		for h := graph.NI(0); int(h) < n; h++ {
+			if im[h] < 0 {
+				continue
+			}
+			b := map[graph.NI]bool{h: true}
+			var st []graph.NI
+			for _, p := range tr.AdjacencyList[h] {
+				if im[p] >= 0 && dominates(h, p) && !b[p] {
+					b[p] = true
+					st = append(st, p)
+				}
+			}
+			if len(st) == 0 {
+				if ok, _ := g.AdjacencyList.HasArc(h, h); !ok {
+					continue
+				}
+			}
+			for len(st) > 0 {
+				x := st[len(st)-1]
+				st = st[:len(st)-1]
+				for _, p := range tr.AdjacencyList[x] {
+					if im[p] >= 0 && !b[p] {
+						b[p] = true
+						st = append(st, p)
+					}
+				}
+			}
+			body[h] = b
+			headers = append(headers, h)
+		}
+		f, gotH := d.Loops()
+		if !reflect.DeepEqual(gotH, headers) {
+			t.Fatalf("headers %v, want %v", gotH, headers)
+		}
+		// innermost loop: smallest body containing the node, excluding
+		// the loop of the node itself
+		for v := graph.NI(0); int(v) < n; v++ {
+			want := graph.NI(-1)
+			for h, b := range body {
+				if h != v && b[v] && (want < 0 || len(b) < len(body[want])) {
+					want = h
+				}
+			}
+			if f.Paths[v].From != want {
+				t.Fatalf("%v: node %d in loop %d, want %d", g, v,
+					f.Paths[v].From, want)
+			}
+		}
+	}
+}
+
 // ------- Labeled examples -------
 
 func ExampleLabeledDirected_NegativeCycles() {
@@ -0,0 +1,190 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "sort"
+
+// dyntopo.go has incremental topological ordering.
+
+// DynamicTopo maintains a topological ordering of a directed acyclic graph
+// as arcs are added.
+//
+// Arcs are added with AddArc, which rejects arcs that would create a cycle.
+// The ordering is updated by reordering only the nodes between the end
+// points of an added arc, rather than by recomputing the whole ordering.
+//
+// Ref: "A Dynamic Topological Sort Algorithm for Directed Acyclic Graphs",
+// David J. Pearce and Paul H. J. Kelly, ACM J. Exp. Algorithmics (2007).
+//
+// Methods are not safe for concurrent use.
+type DynamicTopo struct {
+	G Directed // the graph, retained and modified by AddArc
+
+	tr      AdjacencyList // transpose of G
+	ord     []NI          // topological ordering
+	pos     []int         // position of each node in ord
+	stamp   []uint32      // visit marks for search
+	query   uint32
+	from    []NI // search tree of forward search
+	df, db  []NI // nodes visited by forward and backward search
+	scratch []int
+}
+
+// NewDynamicTopo creates a DynamicTopo for an existing graph.
+//
+// Graph g is retained as field G and arcs are added to it by AddArc.
+// A zero value Directed is valid for starting with an empty graph.
+//
+// If g is cyclic, t will be nil and cycle will be the path of a found cycle,
+// as returned by Topological.
+func NewDynamicTopo(g Directed) (t *DynamicTopo, cycle []NI) {
+	ordering, cycle := g.Topological()
+	if cycle != nil {
+		return nil, cycle
+	}
+	tr, _ := g.Transpose()
+	t = &DynamicTopo{
+		G:     g,
+		tr:    tr.AdjacencyList,
+		ord:   ordering,
+		pos:   make([]int, len(ordering)),
+		stamp: make([]uint32, len(ordering)),
+		from:  make([]NI, len(ordering)),
+	}
+	for i, n := range ordering {
+		t.pos[n] = i
+	}
+	return t, nil
+}
+
+// Ordering returns the current topological ordering.
+//
+// The slice returned is internal to t and is modified by AddArc.  If you
+// need to retain the ordering you must copy it.
+func (t *DynamicTopo) Ordering() []NI {
+	return t.ord
+}
+
+// Position returns the position of node n in the current ordering.
+func (t *DynamicTopo) Position(n NI) int {
+	return t.pos[n]
+}
+
+// grow extends the graph to include node n.  New nodes are placed at the
+// end of the ordering.
+func (t *DynamicTopo) grow(n NI) {
+	for int(n) >= len(t.ord) {
+		x := NI(len(t.ord))
+		t.G.AdjacencyList = append(t.G.AdjacencyList, nil)
+		t.tr = append(t.tr, nil)
+		t.pos = append(t.pos, len(t.ord))
+		t.ord = append(t.ord, x)
+		t.stamp = append(t.stamp, 0)
+		t.from = append(t.from, -1)
+	}
+}
+
+// AddArc adds an arc from fr to to if doing so keeps the graph acyclic.
+//
+// The graph is extended as needed to include nodes fr and to.
+//
+// If the arc is added, ok is true and cycle is nil.  If the arc would
+// create a cycle, the arc is not added, ok is false, and cycle is a path
+// from to back to fr.  The path together with the rejected arc forms the
+// cycle.  For a loop, cycle is just the node.
+func (t *DynamicTopo) AddArc(fr, to NI) (ok bool, cycle []NI) {
+	t.grow(fr)
+	t.grow(to)
+	if fr == to {
+		return false, []NI{fr}
+	}
+	lb, ub := t.pos[to], t.pos[fr]
+	if lb > ub {
+		t.G.AdjacencyList[fr] = append(t.G.AdjacencyList[fr], to)
+		t.tr[to] = append(t.tr[to], fr)
+		return true, nil
+	}
+	t.query++
+	if t.query == 0 {
+		for i := range t.stamp {
+			t.stamp[i] = 0
+		}
+		t.query = 1
+	}
+	// forward search from to, over nodes ordered before fr
+	t.df = t.df[:0]
+	t.from[to] = -1
+	if t.forward(to, fr, ub) {
+		for n := fr; n >= 0; n = t.from[n] {
+			cycle = append(cycle, n)
+		}
+		for i, j := 0, len(cycle)-1; i < j; i, j = i+1, j-1 {
+			cycle[i], cycle[j] = cycle[j], cycle[i]
+		}
+		return false, cycle
+	}
+	// backward search from fr, over nodes ordered after to
+	t.db = t.db[:0]
+	t.backward(fr, lb)
+	t.reorder()
+	t.G.AdjacencyList[fr] = append(t.G.AdjacencyList[fr], to)
+	t.tr[to] = append(t.tr[to], fr)
+	return true, nil
+}
+
+// forward searches from n for node end, visiting nodes at positions up to
+// ub.  It returns true if end is found.
+func (t *DynamicTopo) forward(n, end NI, ub int) bool {
+	t.stamp[n] = t.query
+	t.df = append(t.df, n)
+	for _, to := range t.G.AdjacencyList[n] {
+		if t.stamp[to] == t.query || t.pos[to] > ub {
+			continue
+		}
+		t.from[to] = n
+		if to == end || t.forward(to, end, ub) {
+			return true
+		}
+	}
+	return false
+}
+
+// backward searches the transpose from n, visiting nodes at positions
+// from lb.
+func (t *DynamicTopo) backward(n NI, lb int) {
+	t.stamp[n] = t.query
+	t.db = append(t.db, n)
+	for _, fr := range t.tr[n] {
+		if t.stamp[fr] != t.query && t.pos[fr] >= lb {
+			t.backward(fr, lb)
+		}
+	}
+}
+
+// reorder places the nodes of the backward search before the nodes of the
+// forward search, reusing the positions of both sets.
+func (t *DynamicTopo) reorder() {
+	byPos := func(s []NI) {
+		sort.Slice(s, func(i, j int) bool { return t.pos[s[i]] < t.pos[s[j]] })
+	}
+	byPos(t.db)
+	byPos(t.df)
+	p := t.scratch[:0]
+	for _, n := range t.db {
+		p = append(p, t.pos[n])
+	}
+	for _, n := range t.df {
+		p = append(p, t.pos[n])
+	}
+	sort.Ints(p)
+	i := 0
+	for _, s := range [][]NI{t.db, t.df} {
+		for _, n := range s {
+			t.pos[n] = p[i]
+			t.ord[p[i]] = n
+			i++
+		}
+	}
+	t.scratch = p
+}
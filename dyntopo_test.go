@@ -0,0 +1,86 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDynamicTopo() {
+	t, _ := graph.NewDynamicTopo(graph.Directed{})
+	for _, a := range [][2]graph.NI{{2, 0}, {0, 1}, {3, 2}, {1, 3}, {1, 4}} {
+		ok, cycle := t.AddArc(a[0], a[1])
+		fmt.Println(a, ok, cycle, t.Ordering())
+	}
+	// Output:
+	// [2 0] true [] [2 1 0]
+	// [0 1] true [] [2 0 1]
+	// [3 2] true [] [3 2 0 1]
+	// [1 3] false [3 2 0 1] [3 2 0 1]
+	// [1 4] true [] [3 2 0 1 4]
+}
+
+func TestDynamicTopo(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 100; i++ {
+		n := 1 + r.Intn(12)
+		d, cycle := graph.NewDynamicTopo(graph.Directed{make(graph.AdjacencyList, n)})
+		if cycle != nil {
+			t.Fatal("cycle in empty graph")
+		}
+		for j := 0; j < 3*n; j++ {
+			fr := graph.NI(r.Intn(n))
+			to := graph.NI(r.Intn(n))
+			a := d.G.AdjacencyList
+			// brute force: arc makes a cycle if to reaches fr
+			want := true
+			if fr == to {
+				want = false
+			} else {
+				var f graph.FromList
+				d.G.SpanTree(to, &f)
+				want = f.Paths[fr].Len == 0
+			}
+			ok, cycle := d.AddArc(fr, to)
+			if ok != want {
+				t.Fatalf("add %d->%d: got %t want %t", fr, to, ok, want)
+			}
+			if !ok {
+				if cycle[0] != to || cycle[len(cycle)-1] != fr {
+					t.Fatalf("cycle %v for arc %d->%d", cycle, fr, to)
+				}
+				for k := 1; k < len(cycle); k++ {
+					if ok, _ := a.HasArc(cycle[k-1], cycle[k]); !ok {
+						t.Fatalf("cycle %v uses non-arc", cycle)
+					}
+				}
+			}
+			// validate ordering
+			o := d.Ordering()
+			if len(o) != n {
+				t.Fatal("ordering length")
+			}
+			for k, v := range o {
+				if d.Position(v) != k {
+					t.Fatal("position mismatch")
+				}
+			}
+			for fr, to := range d.G.AdjacencyList {
+				for _, to := range to {
+					if d.Position(graph.NI(fr)) >= d.Position(to) {
+						t.Fatalf("arc %d->%d out of order %v", fr, to, o)
+					}
+				}
+			}
+		}
+	}
+	g := graph.Directed{graph.AdjacencyList{0: {1}, 1: {0}}}
+	if _, cycle := graph.NewDynamicTopo(g); cycle == nil {
+		t.Fatal("no cycle")
+	}
+}
@@ -0,0 +1,199 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"math"
+
+	"github.com/soniakeys/bits"
+)
+
+// flow.go has network flow support for other algorithms.
+
+// flowNet is a residual network for max flow computations.
+//
+// Arcs are stored in pairs, arc x and its reverse x^1.  Capacity cap is
+// the residual capacity, cap0 is the original capacity.  Cost is used only
+// by minCostFlow, for networks built with addCostArc.
+type flowNet struct {
+	adj   [][]int // arc indexes from each node
+	to    []NI
+	cap   []float64
+	cap0  []float64
+	cost  []float64
+	level []int
+	it    []int
+}
+
+func newFlowNet(n int) *flowNet {
+	return &flowNet{
+		adj:   make([][]int, n),
+		level: make([]int, n),
+		it:    make([]int, n),
+	}
+}
+
+// addArc adds an arc fr->to with capacity c and the reverse arc with
+// capacity rc.  For an undirected edge, pass the edge capacity for both.
+func (f *flowNet) addArc(fr, to NI, c, rc float64) {
+	x := len(f.to)
+	f.adj[fr] = append(f.adj[fr], x)
+	f.adj[to] = append(f.adj[to], x+1)
+	f.to = append(f.to, to, fr)
+	f.cap0 = append(f.cap0, c, rc)
+	f.cap = append(f.cap, c, rc)
+}
+
+// addCostArc adds an arc fr->to with capacity c and cost per unit flow
+// cost.  The reverse arc has capacity 0 and the negated cost.
+//
+// Networks for minCostFlow must be built entirely with addCostArc.
+func (f *flowNet) addCostArc(fr, to NI, c, cost float64) {
+	f.addArc(fr, to, c, 0)
+	f.cost = append(f.cost, cost, -cost)
+}
+
+// reset restores original capacities, clearing any flow.
+func (f *flowNet) reset() {
+	copy(f.cap, f.cap0)
+}
+
+// maxFlow computes a maximum flow from s to t with Dinic's algorithm.
+//
+// Computation stops early if the flow reaches limit.  Pass +Inf for
+// an unlimited maximum flow.
+//
+// The flow is left in the residual capacities.  Call reset before
+// computing another flow on the same network.
+func (f *flowNet) maxFlow(s, t NI, limit float64) (flow float64) {
+	for flow < limit && f.bfs(s, t) {
+		for i := range f.it {
+			f.it[i] = 0
+		}
+		for flow < limit {
+			d := f.dfs(s, t, limit-flow)
+			if d == 0 {
+				break
+			}
+			flow += d
+		}
+	}
+	return
+}
+
+// bfs computes levels in the residual network, returning true if t is
+// reachable from s.
+func (f *flowNet) bfs(s, t NI) bool {
+	for i := range f.level {
+		f.level[i] = -1
+	}
+	f.level[s] = 0
+	q := []NI{s}
+	for len(q) > 0 {
+		n := q[0]
+		q = q[1:]
+		for _, x := range f.adj[n] {
+			if to := f.to[x]; f.cap[x] > 0 && f.level[to] < 0 {
+				f.level[to] = f.level[n] + 1
+				q = append(q, to)
+			}
+		}
+	}
+	return f.level[t] >= 0
+}
+
+// dfs finds an augmenting path in the level graph and pushes flow along it.
+func (f *flowNet) dfs(n, t NI, limit float64) float64 {
+	if n == t {
+		return limit
+	}
+	for ; f.it[n] < len(f.adj[n]); f.it[n]++ {
+		x := f.adj[n][f.it[n]]
+		to := f.to[x]
+		if f.cap[x] <= 0 || f.level[to] != f.level[n]+1 {
+			continue
+		}
+		if d := f.dfs(to, t, math.Min(limit, f.cap[x])); d > 0 {
+			f.cap[x] -= d
+			f.cap[x^1] += d
+			return d
+		}
+	}
+	return 0
+}
+
+// sourceSide returns the nodes reachable from s in the residual network.
+//
+// After maxFlow(s, t), this is the source side of a minimum s-t cut.
+func (f *flowNet) sourceSide(s NI) bits.Bits {
+	b := bits.New(len(f.adj))
+	b.SetBit(int(s), 1)
+	q := []NI{s}
+	for len(q) > 0 {
+		n := q[0]
+		q = q[1:]
+		for _, x := range f.adj[n] {
+			if to := f.to[x]; f.cap[x] > 0 && b.Bit(int(to)) == 0 {
+				b.SetBit(int(to), 1)
+				q = append(q, to)
+			}
+		}
+	}
+	return b
+}
+
+// minCostFlow computes a minimum cost flow of up to limit from s to t by
+// successive shortest paths.
+//
+// Paths are found with a queue based Bellman-Ford so reverse arcs with
+// negative cost are handled.  Original arc costs must be non-negative.
+//
+// The flow is left in the residual capacities.  The flow on arc x is
+// the residual capacity of its reverse, cap[x^1], which works also for
+// arcs of infinite capacity.
+func (f *flowNet) minCostFlow(s, t NI, limit float64) (flow, cost float64) {
+	n := len(f.adj)
+	dist := make([]float64, n)
+	via := make([]int, n) // arc followed to each node
+	inQ := make([]bool, n)
+	for flow < limit {
+		for i := range dist {
+			dist[i] = math.Inf(1)
+			via[i] = -1
+		}
+		dist[s] = 0
+		q := []NI{s}
+		inQ[s] = true
+		for len(q) > 0 {
+			n := q[0]
+			q = q[1:]
+			inQ[n] = false
+			for _, x := range f.adj[n] {
+				to := f.to[x]
+				if d := dist[n] + f.cost[x]; f.cap[x] > 0 && d < dist[to] {
+					dist[to] = d
+					via[to] = x
+					if !inQ[to] {
+						inQ[to] = true
+						q = append(q, to)
+					}
+				}
+			}
+		}
+		if via[t] < 0 {
+			break
+		}
+		d := limit - flow
+		for n := t; n != s; n = f.to[via[n]^1] {
+			d = math.Min(d, f.cap[via[n]])
+		}
+		for n := t; n != s; n = f.to[via[n]^1] {
+			f.cap[via[n]] -= d
+			f.cap[via[n]^1] += d
+		}
+		flow += d
+		cost += d * dist[t]
+	}
+	return
+}
@@ -0,0 +1,285 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"context"
+
+	"github.com/soniakeys/bits"
+)
+
+// hamilton.go has backtracking search for Hamiltonian paths and cycles.
+//
+// The search is exponential in the worst case and is intended for small
+// graphs.  It is pruned by checking that each unvisited node still has
+// enough available neighbors and that all unvisited nodes remain reachable
+// from the end of the current path.
+//
+// The emit function is called for each path or cycle found.  The backing
+// slice is reused across emit calls.  If you need to retain the path you
+// must copy it.  Emit must return true to continue the search or false to
+// stop it.
+//
+// If ctx is cancelled the search stops and ctx.Err() is returned.
+// Otherwise the result is nil, whether the search ran to completion or was
+// stopped by emit.
+//
+// Loops are ignored, except that a single node with a loop is a
+// Hamiltonian cycle.  Parallel arcs do not produce duplicate results,
+// except that two parallel edges between two nodes form an undirected
+// Hamiltonian cycle.
+
+// HamiltonianCycles emits all Hamiltonian cycles in a directed graph.
+//
+// A Hamiltonian cycle visits every node exactly once.  Each cycle is emitted
+// once, as a list of all nodes of g starting with node 0.  The arc from
+// the last node back to node 0 is implied.
+//
+// See hamilton.go for emit and ctx conventions.
+func (g Directed) HamiltonianCycles(ctx context.Context, emit func([]NI) bool) error {
+	return newHamSearch(ctx, g.AdjacencyList, false, true, emit).run()
+}
+
+// HamiltonianPaths emits all Hamiltonian paths in a directed graph.
+//
+// A Hamiltonian path visits every node exactly once.  Each path is emitted
+// as a list of all nodes of g in path order.
+//
+// See hamilton.go for emit and ctx conventions.
+func (g Directed) HamiltonianPaths(ctx context.Context, emit func([]NI) bool) error {
+	return newHamSearch(ctx, g.AdjacencyList, false, false, emit).run()
+}
+
+// HamiltonianCycles emits all Hamiltonian cycles in an undirected graph.
+//
+// A Hamiltonian cycle visits every node exactly once.  Each cycle is emitted
+// once, as a list of all nodes of g starting with node 0.  Of the two
+// directions around a cycle, the one emitted has the second node less than
+// the last node.  The edge from the last node back to node 0 is implied.
+//
+// See hamilton.go for emit and ctx conventions.
+func (g Undirected) HamiltonianCycles(ctx context.Context, emit func([]NI) bool) error {
+	return newHamSearch(ctx, g.AdjacencyList, true, true, emit).run()
+}
+
+// HamiltonianPaths emits all Hamiltonian paths in an undirected graph.
+//
+// A Hamiltonian path visits every node exactly once.  Each path is emitted
+// once, as a list of all nodes of g in path order.  Of the two directions
+// along a path, the one emitted has the first node less than the last node.
+//
+// See hamilton.go for emit and ctx conventions.
+func (g Undirected) HamiltonianPaths(ctx context.Context, emit func([]NI) bool) error {
+	return newHamSearch(ctx, g.AdjacencyList, true, false, emit).run()
+}
+
+type hamSearch struct {
+	ctx         context.Context
+	a           AdjacencyList
+	undir       bool
+	cycle       bool
+	emit        func([]NI) bool
+	out, in     []bits.Bits // neighbors, excluding loops
+	nb          [][]NI      // distinct out neighbors
+	path        []NI
+	unvisited   bits.Bits
+	inAv, outAv bits.Bits // candidate predecessors and successors
+	tmp         bits.Bits
+	reached     bits.Bits
+	err         error
+}
+
+func newHamSearch(ctx context.Context, a AdjacencyList, undir, cycle bool, emit func([]NI) bool) *hamSearch {
+	n := len(a)
+	h := &hamSearch{
+		ctx:       ctx,
+		a:         a,
+		undir:     undir,
+		cycle:     cycle,
+		emit:      emit,
+		out:       make([]bits.Bits, n),
+		in:        make([]bits.Bits, n),
+		nb:        make([][]NI, n),
+		unvisited: bits.New(n),
+		inAv:      bits.New(n),
+		outAv:     bits.New(n),
+		tmp:       bits.New(n),
+		reached:   bits.New(n),
+	}
+	for i := range a {
+		h.out[i] = bits.New(n)
+		h.in[i] = bits.New(n)
+	}
+	for fr, to := range a {
+		for _, to := range to {
+			if to != NI(fr) && h.out[fr].Bit(int(to)) == 0 {
+				h.out[fr].SetBit(int(to), 1)
+				h.in[to].SetBit(fr, 1)
+				h.nb[fr] = append(h.nb[fr], to)
+			}
+		}
+	}
+	return h
+}
+
+func (h *hamSearch) run() error {
+	n := len(h.a)
+	switch {
+	case n == 0:
+		return nil
+	case n == 1:
+		if !h.cycle {
+			h.emit([]NI{0})
+			return nil
+		}
+		for _, to := range h.a[0] {
+			if to == 0 {
+				h.emit([]NI{0})
+				break
+			}
+		}
+		return nil
+	case n == 2 && h.cycle && h.undir:
+		// a 2-node undirected cycle needs parallel edges
+		m := 0
+		for _, to := range h.a[0] {
+			if to == 1 {
+				m++
+			}
+		}
+		if m >= 2 {
+			h.emit([]NI{0, 1})
+		}
+		return nil
+	}
+	h.path = make([]NI, 0, n)
+	h.unvisited.SetAll()
+	// cycles can start anywhere, so start at 0.  directed paths must start
+	// at a node without in-arcs if there is one.
+	starts := []NI{0}
+	if !h.cycle {
+		starts = starts[:0]
+		for i := range h.a {
+			if !h.undir && h.in[i].AllZeros() {
+				starts = append(starts, NI(i))
+			}
+		}
+		switch {
+		case len(starts) > 1:
+			return nil
+		case len(starts) == 0:
+			for i := range h.a {
+				starts = append(starts, NI(i))
+			}
+		}
+	}
+	for _, s := range starts {
+		h.path = append(h.path[:0], s)
+		h.unvisited.SetBit(int(s), 0)
+		if !h.extend() {
+			break
+		}
+		h.unvisited.SetBit(int(s), 1)
+	}
+	return h.err
+}
+
+// extend extends the current path, returning false to stop the search.
+func (h *hamSearch) extend() bool {
+	n := len(h.a)
+	p := h.path
+	if len(p) == n {
+		switch {
+		case h.cycle && h.out[p[n-1]].Bit(int(p[0])) == 0:
+			return true
+		case h.cycle && h.undir && p[1] > p[n-1]:
+			return true
+		case !h.cycle && h.undir && p[0] > p[n-1]:
+			return true
+		}
+		return h.emit(p)
+	}
+	if h.err = h.ctx.Err(); h.err != nil {
+		return false
+	}
+	cur := p[len(p)-1]
+	if !h.feasible(cur) {
+		return true
+	}
+	for _, to := range h.nb[cur] {
+		if h.unvisited.Bit(int(to)) == 0 {
+			continue
+		}
+		h.path = append(h.path, to)
+		h.unvisited.SetBit(int(to), 0)
+		ok := h.extend()
+		h.unvisited.SetBit(int(to), 1)
+		h.path = h.path[:len(h.path)-1]
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// feasible checks necessary conditions for completing the current path,
+// which ends at node cur.
+func (h *hamSearch) feasible(cur NI) bool {
+	// degree checks.  each unvisited node needs a predecessor among
+	// unvisited nodes and cur, and a successor among unvisited nodes, and
+	// start in the case of a cycle.  for paths one unvisited node can be
+	// the end and lack a successor.
+	h.inAv.Set(h.unvisited)
+	h.inAv.SetBit(int(cur), 1)
+	h.outAv.Set(h.unvisited)
+	if h.cycle {
+		h.outAv.SetBit(int(h.path[0]), 1)
+	}
+	if h.undir {
+		h.inAv.Or(h.inAv, h.outAv)
+	}
+	nEnds := 0
+	ok := h.unvisited.IterateOnes(func(v int) bool {
+		h.tmp.And(h.in[v], h.inAv)
+		if h.undir {
+			// predecessor and successor are distinct neighbors
+			switch h.tmp.OnesCount() {
+			case 0:
+				return false
+			case 1:
+				nEnds++
+				return !h.cycle && nEnds == 1
+			}
+			return true
+		}
+		if h.tmp.AllZeros() {
+			return false
+		}
+		h.tmp.And(h.out[v], h.outAv)
+		if h.tmp.AllZeros() {
+			nEnds++
+			return !h.cycle && nEnds == 1
+		}
+		return true
+	})
+	if !ok {
+		return false
+	}
+	// connectivity check.  all unvisited nodes must be reachable from cur
+	// through unvisited nodes.
+	h.reached.ClearAll()
+	frontier := []NI{cur}
+	for len(frontier) > 0 {
+		v := frontier[len(frontier)-1]
+		frontier = frontier[:len(frontier)-1]
+		h.tmp.And(h.out[v], h.unvisited)
+		h.tmp.AndNot(h.tmp, h.reached)
+		h.tmp.IterateOnes(func(to int) bool {
+			h.reached.SetBit(to, 1)
+			frontier = append(frontier, NI(to))
+			return true
+		})
+	}
+	return h.reached.Equal(h.unvisited)
+}
@@ -0,0 +1,209 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleDirected_HamiltonianCycles() {
+	//   0 --> 1
+	//   ^ \   |
+	//   |  v  v
+	//   3 <-- 2
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {0},
+	}}
+	g.HamiltonianCycles(context.Background(), func(c []graph.NI) bool {
+		fmt.Println(c)
+		return true
+	})
+	// Output:
+	// [0 1 2 3]
+}
+
+func ExampleDirected_HamiltonianPaths() {
+	//   0 --> 1
+	//   ^ \   |
+	//   |  v  v
+	//   3 <-- 2
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1, 2},
+		1: {2},
+		2: {3},
+		3: {0},
+	}}
+	g.HamiltonianPaths(context.Background(), func(p []graph.NI) bool {
+		fmt.Println(p)
+		return true
+	})
+	// Output:
+	// [0 1 2 3]
+	// [1 2 3 0]
+	// [2 3 0 1]
+	// [3 0 1 2]
+}
+
+func ExampleUndirected_HamiltonianCycles() {
+	// complete graph K4
+	var g graph.Undirected
+	for i := graph.NI(0); i < 4; i++ {
+		for j := i + 1; j < 4; j++ {
+			g.AddEdge(i, j)
+		}
+	}
+	g.HamiltonianCycles(context.Background(), func(c []graph.NI) bool {
+		fmt.Println(c)
+		return true
+	})
+	// Output:
+	// [0 1 2 3]
+	// [0 1 3 2]
+	// [0 2 1 3]
+}
+
+func ExampleUndirected_HamiltonianPaths() {
+	// 0--1--2
+	//    |
+	//    3
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(1, 3)
+	fmt.Println(g.HamiltonianPaths(context.Background(),
+		func(p []graph.NI) bool {
+			fmt.Println(p)
+			return true
+		}))
+	g.AddEdge(2, 3)
+	fmt.Println(g.HamiltonianPaths(context.Background(),
+		func(p []graph.NI) bool {
+			fmt.Println(p)
+			return true
+		}))
+	// Output:
+	// <nil>
+	// [0 1 2 3]
+	// [0 1 3 2]
+	// <nil>
+}
+
+func TestHamiltonian(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 300; i++ {
+		n := 1 + r.Intn(7)
+		var a graph.AdjacencyList
+		undir := i%2 == 0
+		if undir {
+			a = graph.GnmUndirected(n, r.Intn(n*(n-1)/2+1), r).AdjacencyList
+		} else {
+			a = graph.GnmDirected(n, r.Intn(n*(n-1)+1), r).AdjacencyList
+		}
+		arc := func(fr, to graph.NI) bool {
+			ok, _ := a.HasArc(fr, to)
+			return ok
+		}
+		// brute force, keyed by fmt of the node list
+		wantP := map[string]bool{}
+		wantC := map[string]bool{}
+		p := make([]graph.NI, n)
+		for i := range p {
+			p[i] = graph.NI(i)
+		}
+		var perm func(int)
+		perm = func(k int) {
+			if k == n {
+				for i := 1; i < n; i++ {
+					if !arc(p[i-1], p[i]) {
+						return
+					}
+				}
+				if !undir || p[0] < p[n-1] || n == 1 {
+					wantP[fmt.Sprint(p)] = true
+				}
+				if (n > 2 || n == 2 && !undir) && p[0] == 0 &&
+					arc(p[n-1], p[0]) && (!undir || p[1] < p[n-1]) {
+					wantC[fmt.Sprint(p)] = true
+				}
+				return
+			}
+			for i := k; i < n; i++ {
+				p[k], p[i] = p[i], p[k]
+				perm(k + 1)
+				p[k], p[i] = p[i], p[k]
+			}
+		}
+		perm(0)
+		for j, want := range []map[string]bool{wantP, wantC} {
+			got := map[string]bool{}
+			emit := func(p []graph.NI) bool {
+				s := fmt.Sprint(p)
+				if got[s] {
+					t.Fatal("duplicate", s)
+				}
+				got[s] = true
+				return true
+			}
+			var err error
+			switch {
+			case undir && j == 0:
+				err = graph.Undirected{a}.HamiltonianPaths(context.Background(), emit)
+			case undir:
+				err = graph.Undirected{a}.HamiltonianCycles(context.Background(), emit)
+			case j == 0:
+				err = graph.Directed{a}.HamiltonianPaths(context.Background(), emit)
+			default:
+				err = graph.Directed{a}.HamiltonianCycles(context.Background(), emit)
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(want) {
+				t.Fatalf("undir %t cycle %t: got %v want %v", undir, j == 1,
+					got, want)
+			}
+			for s := range want {
+				if !got[s] {
+					t.Fatalf("missing %s", s)
+				}
+			}
+		}
+	}
+}
+
+func TestHamiltonianCancel(t *testing.T) {
+	var g graph.Undirected
+	for i := graph.NI(0); i < 12; i++ {
+		for j := i + 1; j < 12; j++ {
+			g.AddEdge(i, j)
+		}
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	n := 0
+	err := g.HamiltonianCycles(ctx, func([]graph.NI) bool {
+		if n++; n == 10 {
+			cancel()
+		}
+		return true
+	})
+	if err != context.Canceled || n != 10 {
+		t.Fatal(err, n)
+	}
+	n = 0
+	err = g.HamiltonianPaths(context.Background(), func([]graph.NI) bool {
+		n++
+		return false
+	})
+	if err != nil || n != 1 {
+		t.Fatal(err, n)
+	}
+}
@@ -0,0 +1,456 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "sort"
+
+// iso.go has isomorphism and subgraph isomorphism methods.
+//
+// The search is VF2 with a VF2++ style node ordering.  See "A (Sub)Graph
+// Isomorphism Algorithm for Matching Large Graphs", Cordella et al., IEEE
+// Trans. PAMI Vol. 26, No. 10, October 2004, and "VF2++ — An improved
+// subgraph isomorphism algorithm", Jüttner and Madarasi, Discrete Applied
+// Mathematics 242 (2018).
+//
+// Labeled and unlabeled methods share a single implementation.  Unlabeled
+// graphs are matched as if all arcs had label 0.
+
+// Isomorphism finds an isomorphism between graphs g and h.
+//
+// If g and h are isomorphic, Isomorphism returns a mapping m and ok = true.
+// The mapping is from nodes of h to nodes of g, so that for each arc
+// fr->to in h, there is an arc m[fr]->m[to] in g.  That is, after
+// h.Permute(m), h.Equal(g) would be true.  (Convert m to []int first.)
+//
+// The method works for directed and undirected graphs, and for multigraphs.
+// Graphs g and h should be of the same kind though, either both directed or
+// both undirected.  Arc multiplicities must correspond for g and h to be
+// isomorphic.
+//
+// If g and h are not isomorphic, Isomorphism returns nil, false.
+//
+// See also LabeledAdjacencyList.Isomorphism for a labeled version.
+func (g AdjacencyList) Isomorphism(h AdjacencyList) (m []NI, ok bool) {
+	return newVF2(labelZero(g), labelZero(h), vf2Iso).iso()
+}
+
+// SubgraphIsomorphisms finds subgraphs of g isomorphic to graph h.
+//
+// The method calls the emit argument for each mapping from nodes of h to
+// nodes of g that preserves the arcs of h, as long as emit returns true.
+// If emit returns false, SubgraphIsomorphisms returns immediately.
+//
+// The mapping m passed to emit has length len(h).  For each arc fr->to of
+// h, there is an arc m[fr]->m[to] in g.  The backing slice for m is reused
+// across emit calls.  If you need to retain the mapping you must copy it.
+//
+// If argument induced is true, only node-induced subgraphs are matched.
+// That is, for any two nodes of h, g has an arc between the mapped nodes
+// if and only if h has an arc between the nodes.  If induced is false, g
+// may have additional arcs between the mapped nodes.  (This is sometimes
+// called a monomorphism.)
+//
+// Note that a subgraph with symmetry will be emitted once for each
+// automorphism of h.
+//
+// See also LabeledAdjacencyList.SubgraphIsomorphisms for a labeled version.
+func (g AdjacencyList) SubgraphIsomorphisms(h AdjacencyList, induced bool, emit func(m []NI) bool) {
+	mode := vf2Mono
+	if induced {
+		mode = vf2Induced
+	}
+	newVF2(labelZero(g), labelZero(h), mode).match(emit)
+}
+
+// Isomorphism finds an isomorphism between labeled graphs g and h.
+//
+// This is the labeled version of AdjacencyList.Isomorphism.  In addition
+// to nodes and arcs corresponding, arc labels must match.  That is, for each
+// arc fr->to in h with label l, there must be an arc m[fr]->m[to] in g also
+// with label l.
+func (g LabeledAdjacencyList) Isomorphism(h LabeledAdjacencyList) (m []NI, ok bool) {
+	return newVF2(g, h, vf2Iso).iso()
+}
+
+// SubgraphIsomorphisms finds subgraphs of g isomorphic to labeled graph h.
+//
+// This is the labeled version of AdjacencyList.SubgraphIsomorphisms.  In
+// addition to nodes and arcs corresponding, arc labels must match.  That is,
+// for each arc fr->to in h with label l, there must be an arc m[fr]->m[to]
+// in g also with label l.
+func (g LabeledAdjacencyList) SubgraphIsomorphisms(h LabeledAdjacencyList, induced bool, emit func(m []NI) bool) {
+	mode := vf2Mono
+	if induced {
+		mode = vf2Induced
+	}
+	newVF2(g, h, mode).match(emit)
+}
+
+// labelZero makes a labeled copy of g with all labels 0.
+func labelZero(g AdjacencyList) LabeledAdjacencyList {
+	l := make(LabeledAdjacencyList, len(g))
+	for fr, to := range g {
+		lt := make([]Half, len(to))
+		for i, to := range to {
+			lt[i].To = to
+		}
+		l[fr] = lt
+	}
+	return l
+}
+
+type vf2Mode int
+
+const (
+	vf2Iso     vf2Mode = iota // isomorphism
+	vf2Induced                // node-induced subgraph isomorphism
+	vf2Mono                   // subgraph monomorphism
+)
+
+// vf2 holds state for a VF2 search.
+//
+// Graph 1 is the target graph, graph 2 the pattern graph.  Out and in
+// arc lists are sorted by node, then label, so that the labels of parallel
+// arcs between two nodes can be located by binary search.
+type vf2 struct {
+	mode         vf2Mode
+	out1, in1    LabeledAdjacencyList
+	out2, in2    LabeledAdjacencyList
+	core1, core2 []NI  // mappings, -1 for unmapped
+	tOut1, tIn1  []int // terminal sets, depth entered or 0
+	tOut2, tIn2  []int
+	order        []NI     // matching order of pattern nodes
+	parent       []vf2Arc // mapped neighbor of each pattern node in order
+	depth        int
+}
+
+// vf2Arc identifies an earlier-ordered neighbor of a pattern node, used
+// to generate candidates.  If out is true the pattern node has an arc from
+// nb, otherwise an arc to nb.  nb is -1 for a node with no earlier neighbor.
+type vf2Arc struct {
+	nb  NI
+	out bool
+}
+
+func newVF2(g1, g2 LabeledAdjacencyList, mode vf2Mode) *vf2 {
+	s := &vf2{mode: mode}
+	s.out1, s.in1 = vf2Arcs(g1)
+	s.out2, s.in2 = vf2Arcs(g2)
+	s.core1 = make([]NI, len(g1))
+	for i := range s.core1 {
+		s.core1[i] = -1
+	}
+	s.core2 = make([]NI, len(g2))
+	for i := range s.core2 {
+		s.core2[i] = -1
+	}
+	s.tOut1 = make([]int, len(g1))
+	s.tIn1 = make([]int, len(g1))
+	s.tOut2 = make([]int, len(g2))
+	s.tIn2 = make([]int, len(g2))
+	s.orderPattern()
+	return s
+}
+
+// vf2Arcs constructs sorted copies of the out and in arc lists of g.
+func vf2Arcs(g LabeledAdjacencyList) (out, in LabeledAdjacencyList) {
+	out = make(LabeledAdjacencyList, len(g))
+	in = make(LabeledAdjacencyList, len(g))
+	for fr, to := range g {
+		out[fr] = append([]Half{}, to...)
+		for _, to := range to {
+			in[to.To] = append(in[to.To], Half{NI(fr), to.Label})
+		}
+	}
+	less := func(l []Half) func(i, j int) bool {
+		return func(i, j int) bool {
+			if l[i].To != l[j].To {
+				return l[i].To < l[j].To
+			}
+			return l[i].Label < l[j].Label
+		}
+	}
+	for n := range out {
+		sort.Slice(out[n], less(out[n]))
+		sort.Slice(in[n], less(in[n]))
+	}
+	return
+}
+
+// arcRange returns the sorted sublist of arcs to node n.
+func arcRange(l []Half, n NI) []Half {
+	i := sort.Search(len(l), func(i int) bool { return l[i].To >= n })
+	j := i
+	for j < len(l) && l[j].To == n {
+		j++
+	}
+	return l[i:j]
+}
+
+// orderPattern computes the matching order for pattern nodes.
+//
+// Following VF2++, nodes are ordered to keep the matched subgraph connected
+// as long as possible, choosing nodes with the most connections to already
+// ordered nodes first and breaking ties by degree.
+func (s *vf2) orderPattern() {
+	n2 := len(s.out2)
+	s.order = make([]NI, 0, n2)
+	s.parent = make([]vf2Arc, 0, n2)
+	conn := make([]int, n2) // arcs to ordered nodes, -1 when ordered
+	deg := func(n int) int { return len(s.out2[n]) + len(s.in2[n]) }
+	for len(s.order) < n2 {
+		best := -1
+		for n, c := range conn {
+			if c < 0 {
+				continue
+			}
+			if best < 0 || c > conn[best] ||
+				c == conn[best] && deg(n) > deg(best) {
+				best = n
+			}
+		}
+		p := vf2Arc{nb: -1}
+		for _, fr := range s.in2[best] {
+			if conn[fr.To] < 0 {
+				p = vf2Arc{fr.To, true}
+				break
+			}
+		}
+		if p.nb < 0 {
+			for _, to := range s.out2[best] {
+				if conn[to.To] < 0 {
+					p = vf2Arc{to.To, false}
+					break
+				}
+			}
+		}
+		s.order = append(s.order, NI(best))
+		s.parent = append(s.parent, p)
+		conn[best] = -1
+		for _, to := range s.out2[best] {
+			if conn[to.To] >= 0 {
+				conn[to.To]++
+			}
+		}
+		for _, fr := range s.in2[best] {
+			if conn[fr.To] >= 0 {
+				conn[fr.To]++
+			}
+		}
+	}
+}
+
+// iso runs the search for a full isomorphism.
+func (s *vf2) iso() (m []NI, ok bool) {
+	if len(s.out1) != len(s.out2) || !s.sameDegrees() {
+		return nil, false
+	}
+	s.match(func(c []NI) bool {
+		m = append([]NI{}, c...)
+		ok = true
+		return false
+	})
+	return
+}
+
+// sameDegrees is a quick test that degree sequences match.
+func (s *vf2) sameDegrees() bool {
+	type deg struct{ out, in int }
+	c := map[deg]int{}
+	for n := range s.out1 {
+		c[deg{len(s.out1[n]), len(s.in1[n])}]++
+	}
+	for n := range s.out2 {
+		d := deg{len(s.out2[n]), len(s.in2[n])}
+		if c[d] == 0 {
+			return false
+		}
+		c[d]--
+	}
+	return true
+}
+
+// match runs the search, calling emit for each complete mapping.
+func (s *vf2) match(emit func([]NI) bool) {
+	if len(s.out2) > len(s.out1) {
+		return
+	}
+	s.extend(emit)
+}
+
+func (s *vf2) extend(emit func([]NI) bool) bool {
+	if s.depth == len(s.order) {
+		return emit(s.core2)
+	}
+	n2 := s.order[s.depth]
+	try := func(n1 NI) bool {
+		if s.core1[n1] >= 0 || !s.feasible(n1, n2) {
+			return true
+		}
+		s.push(n1, n2)
+		ok := s.extend(emit)
+		s.pop(n1, n2)
+		return ok
+	}
+	p := s.parent[s.depth]
+	if p.nb < 0 {
+		for n1 := range s.out1 {
+			if !try(NI(n1)) {
+				return false
+			}
+		}
+		return true
+	}
+	// candidates are neighbors of the image of the parent node.
+	// parallel arcs give duplicate candidates, skip them.
+	var cand []Half
+	if p.out {
+		cand = s.out1[s.core2[p.nb]]
+	} else {
+		cand = s.in1[s.core2[p.nb]]
+	}
+	for i, c := range cand {
+		if i > 0 && cand[i-1].To == c.To {
+			continue
+		}
+		if !try(c.To) {
+			return false
+		}
+	}
+	return true
+}
+
+// compatible tests label lists of arcs between a node pair in each graph.
+func (s *vf2) compatible(l1, l2 []Half) bool {
+	if s.mode != vf2Mono {
+		if len(l1) != len(l2) {
+			return false
+		}
+		for i, h := range l1 {
+			if h.Label != l2[i].Label {
+				return false
+			}
+		}
+		return true
+	}
+	// l2 must be a sub-multiset of l1.  both are sorted by label.
+	i := 0
+	for _, h := range l2 {
+		for i < len(l1) && l1[i].Label < h.Label {
+			i++
+		}
+		if i == len(l1) || l1[i].Label != h.Label {
+			return false
+		}
+		i++
+	}
+	return true
+}
+
+// feasible tests if pair n1, n2 can be added to the current mapping.
+func (s *vf2) feasible(n1, n2 NI) bool {
+	o1, i1, o2, i2 := s.out1[n1], s.in1[n1], s.out2[n2], s.in2[n2]
+	if s.mode == vf2Iso {
+		if len(o1) != len(o2) || len(i1) != len(i2) {
+			return false
+		}
+	} else if len(o1) < len(o2) || len(i1) < len(i2) {
+		return false
+	}
+	// loops
+	if !s.compatible(arcRange(o1, n1), arcRange(o2, n2)) {
+		return false
+	}
+	// arcs to and from mapped nodes, pattern side
+	for x, to := range o2 {
+		if m := s.core2[to.To]; m >= 0 && (x == 0 || o2[x-1].To != to.To) {
+			if !s.compatible(arcRange(o1, m), arcRange(o2, to.To)) {
+				return false
+			}
+		}
+	}
+	for x, fr := range i2 {
+		if m := s.core2[fr.To]; m >= 0 && (x == 0 || i2[x-1].To != fr.To) {
+			if !s.compatible(arcRange(i1, m), arcRange(i2, fr.To)) {
+				return false
+			}
+		}
+	}
+	// target side.  for induced and iso, arcs to and from mapped target
+	// nodes must have pattern counterparts.  also count terminal arcs
+	// for look-ahead.
+	var t1o, t1i, t2o, t2i int
+	for _, to := range o1 {
+		switch m := s.core1[to.To]; {
+		case m >= 0:
+			if s.mode != vf2Mono && len(arcRange(o2, m)) == 0 {
+				return false
+			}
+		case to.To != n1 && (s.tOut1[to.To] > 0 || s.tIn1[to.To] > 0):
+			t1o++
+		}
+	}
+	for _, fr := range i1 {
+		switch m := s.core1[fr.To]; {
+		case m >= 0:
+			if s.mode != vf2Mono && len(arcRange(i2, m)) == 0 {
+				return false
+			}
+		case fr.To != n1 && (s.tOut1[fr.To] > 0 || s.tIn1[fr.To] > 0):
+			t1i++
+		}
+	}
+	for _, to := range o2 {
+		if s.core2[to.To] < 0 && to.To != n2 &&
+			(s.tOut2[to.To] > 0 || s.tIn2[to.To] > 0) {
+			t2o++
+		}
+	}
+	for _, fr := range i2 {
+		if s.core2[fr.To] < 0 && fr.To != n2 &&
+			(s.tOut2[fr.To] > 0 || s.tIn2[fr.To] > 0) {
+			t2i++
+		}
+	}
+	if s.mode == vf2Iso {
+		return t1o == t2o && t1i == t2i
+	}
+	return t1o >= t2o && t1i >= t2i
+}
+
+func (s *vf2) push(n1, n2 NI) {
+	s.depth++
+	d := s.depth
+	s.core1[n1] = n2
+	s.core2[n2] = n1
+	enter := func(t []int, l []Half) {
+		for _, h := range l {
+			if t[h.To] == 0 {
+				t[h.To] = d
+			}
+		}
+	}
+	enter(s.tOut1, s.out1[n1])
+	enter(s.tIn1, s.in1[n1])
+	enter(s.tOut2, s.out2[n2])
+	enter(s.tIn2, s.in2[n2])
+}
+
+func (s *vf2) pop(n1, n2 NI) {
+	d := s.depth
+	leave := func(t []int, l []Half) {
+		for _, h := range l {
+			if t[h.To] == d {
+				t[h.To] = 0
+			}
+		}
+	}
+	leave(s.tOut1, s.out1[n1])
+	leave(s.tIn1, s.in1[n1])
+	leave(s.tOut2, s.out2[n2])
+	leave(s.tIn2, s.in2[n2])
+	s.core1[n1] = -1
+	s.core2[n2] = -1
+	s.depth--
+}
@@ -0,0 +1,190 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleAdjacencyList_Isomorphism() {
+	// g:             h:
+	// 0 -> 1 -> 2    2 -> 0 -> 1
+	//      |              |
+	//      v              v
+	//      3              3
+	g := graph.AdjacencyList{
+		0: {1},
+		1: {2, 3},
+		3: nil,
+	}
+	h := graph.AdjacencyList{
+		0: {1, 3},
+		2: {0},
+		3: nil,
+	}
+	m, ok := g.Isomorphism(h)
+	fmt.Println(m, ok)
+	p := make([]int, len(m))
+	for i, n := range m {
+		p[i] = int(n)
+	}
+	h.Permute(p)
+	fmt.Println(h.Equal(g))
+	// Output:
+	// [1 2 0 3] true
+	// true
+}
+
+func ExampleAdjacencyList_SubgraphIsomorphisms() {
+	//    0
+	//   / \
+	//  1---2---3
+	//       \ /
+	//        4
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(0, 2)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 4)
+	g.AddEdge(3, 4)
+	// path of three nodes
+	var h graph.Undirected
+	h.AddEdge(0, 1)
+	h.AddEdge(1, 2)
+	fmt.Println("induced:")
+	g.SubgraphIsomorphisms(h.AdjacencyList, true, func(m []graph.NI) bool {
+		fmt.Println(m)
+		return true
+	})
+	n := 0
+	g.SubgraphIsomorphisms(h.AdjacencyList, false, func([]graph.NI) bool {
+		n++
+		return true
+	})
+	fmt.Println("not induced:", n)
+	// Output:
+	// induced:
+	// [0 2 3]
+	// [0 2 4]
+	// [1 2 3]
+	// [1 2 4]
+	// [3 2 0]
+	// [3 2 1]
+	// [4 2 0]
+	// [4 2 1]
+	// not induced: 20
+}
+
+func ExampleLabeledAdjacencyList_Isomorphism() {
+	// g:               h:
+	// 0 -(5)-> 1       0 <-(6)- 1 -(5)-> 2
+	//  \
+	//  (6)-> 2
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 5}, {To: 2, Label: 6}},
+		2: nil,
+	}
+	h := graph.LabeledAdjacencyList{
+		1: {{To: 0, Label: 6}, {To: 2, Label: 5}},
+		2: nil,
+	}
+	fmt.Println(g.Isomorphism(h))
+	h[1][1].Label = 6
+	fmt.Println(g.Isomorphism(h))
+	// Output:
+	// [2 0 1] true
+	// [] false
+}
+
+func ExampleLabeledAdjacencyList_SubgraphIsomorphisms() {
+	// 0 -(1)-> 1 -(2)-> 2 -(1)-> 3 -(2)-> 4
+	g := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}},
+		1: {{To: 2, Label: 2}},
+		2: {{To: 3, Label: 1}},
+		3: {{To: 4, Label: 2}},
+		4: nil,
+	}
+	// 0 -(2)-> 1 -(1)-> 2
+	h := graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 2}},
+		1: {{To: 2, Label: 1}},
+		2: nil,
+	}
+	g.SubgraphIsomorphisms(h, true, func(m []graph.NI) bool {
+		fmt.Println(m)
+		return true
+	})
+	// Output:
+	// [1 2 3]
+}
+
+func TestIsomorphism(t *testing.T) {
+	r := rand.New(rand.NewSource(7))
+	for i := 0; i < 50; i++ {
+		g := graph.GnmDirected(20, 40, r).AdjacencyList
+		h, _ := g.Copy()
+		p := r.Perm(len(h))
+		h.Permute(p)
+		m, ok := g.Isomorphism(h)
+		if !ok {
+			t.Fatal("isomorphism not found")
+		}
+		mp := make([]int, len(m))
+		for i, n := range m {
+			mp[i] = int(n)
+		}
+		h.Permute(mp)
+		if !h.Equal(g) {
+			t.Fatal("invalid mapping")
+		}
+		// remove one arc and graphs are no longer isomorphic
+		for fr, to := range h {
+			if len(to) > 0 {
+				h[fr] = to[1:]
+				break
+			}
+		}
+		if _, ok := g.Isomorphism(h); ok {
+			t.Fatal("non-isomorphic graphs found isomorphic")
+		}
+	}
+}
+
+func TestSubgraphIsomorphisms(t *testing.T) {
+	// count triangles, each is found 6 times
+	r := rand.New(rand.NewSource(7))
+	g, _ := graph.GnpUndirected(25, .3, r)
+	var tri graph.Undirected
+	tri.AddEdge(0, 1)
+	tri.AddEdge(1, 2)
+	tri.AddEdge(2, 0)
+	n := 0
+	g.SubgraphIsomorphisms(tri.AdjacencyList, true, func([]graph.NI) bool {
+		n++
+		return true
+	})
+	c := 0
+	a := g.AdjacencyList
+	for n1 := range a {
+		for n2 := n1 + 1; n2 < len(a); n2++ {
+			for n3 := n2 + 1; n3 < len(a); n3++ {
+				h12, _ := g.HasArc(graph.NI(n1), graph.NI(n2))
+				h23, _ := g.HasArc(graph.NI(n2), graph.NI(n3))
+				h31, _ := g.HasArc(graph.NI(n3), graph.NI(n1))
+				if h12 && h23 && h31 {
+					c++
+				}
+			}
+		}
+	}
+	if n != 6*c {
+		t.Fatalf("found %d mappings, want %d", n, 6*c)
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "math/bits"
+
+// lca.go has an index for lowest common ancestor queries on trees.
+
+// LCAIndex answers lowest common ancestor queries on a FromList forest.
+//
+// The index takes O(n log n) time and space to build and answers queries
+// in constant time.  Nodes are numbered in a depth-first preorder.  For
+// nodes a and b with a before b in the preorder, the lowest common ancestor
+// is the parent of the node of least depth following a, up to and including
+// b.  Least depth queries are answered with a sparse table.
+//
+// Ref: "The LCA Problem Revisited", Michael A. Bender and Martín
+// Farach-Colton, LATIN (2000).
+//
+// Methods are safe for concurrent use.
+type LCAIndex struct {
+	from  []NI    // parent of each node
+	root  []NI    // root of each node, -1 for nodes not in the forest
+	pre   []int32 // preorder number of each node
+	depth []int32 // depth of each node
+	min   [][]NI  // sparse table of least depth nodes over the preorder
+}
+
+// NewLCAIndex builds an LCAIndex for the forest represented by f.
+//
+// Only the From members of f.Paths are used.  Len members need not be
+// populated.  Nodes on a cycle of f, or with a path back to a cycle, are
+// not part of the forest and queries on them return -1.
+func NewLCAIndex(f FromList) *LCAIndex {
+	p := f.Paths
+	x := &LCAIndex{
+		from:  make([]NI, len(p)),
+		root:  make([]NI, len(p)),
+		pre:   make([]int32, len(p)),
+		depth: make([]int32, len(p)),
+	}
+	// child lists, in compressed form
+	start := make([]int, len(p)+1)
+	for n, e := range p {
+		x.from[n] = e.From
+		x.root[n] = -1
+		if e.From >= 0 {
+			start[e.From+1]++
+		}
+	}
+	for n := 1; n <= len(p); n++ {
+		start[n] += start[n-1]
+	}
+	child := make([]NI, start[len(p)])
+	next := append([]int{}, start[:len(p)]...)
+	for n, e := range p {
+		if e.From >= 0 {
+			child[next[e.From]] = NI(n)
+			next[e.From]++
+		}
+	}
+	// iterative preorder from each root
+	order := make([]NI, 0, len(p))
+	var stack []NI
+	for r, e := range p {
+		if e.From >= 0 {
+			continue
+		}
+		stack = append(stack[:0], NI(r))
+		x.depth[r] = 0
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			x.root[n] = NI(r)
+			x.pre[n] = int32(len(order))
+			order = append(order, n)
+			for i := start[n+1] - 1; i >= start[n]; i-- {
+				c := child[i]
+				x.depth[c] = x.depth[n] + 1
+				stack = append(stack, c)
+			}
+		}
+	}
+	// sparse table
+	x.min = [][]NI{order}
+	for w := 1; 2*w <= len(order); w *= 2 {
+		prev := x.min[len(x.min)-1]
+		m := make([]NI, len(order)-2*w+1)
+		for i := range m {
+			m[i] = x.shallower(prev[i], prev[i+w])
+		}
+		x.min = append(x.min, m)
+	}
+	return x
+}
+
+func (x *LCAIndex) shallower(a, b NI) NI {
+	if x.depth[b] < x.depth[a] {
+		return b
+	}
+	return a
+}
+
+// CommonStart returns the lowest common ancestor of nodes a and b.
+//
+// A node is its own ancestor, so if a is an ancestor of b, a is returned.
+// It returns -1 if a and b are in different trees.
+//
+// The result is the same as FromList.CommonStart but does not rely on
+// populated Len members.
+func (x *LCAIndex) CommonStart(a, b NI) NI {
+	if x.root[a] < 0 || x.root[a] != x.root[b] {
+		return -1
+	}
+	if a == b {
+		return a
+	}
+	l, r := x.pre[a], x.pre[b]
+	if l > r {
+		l, r = r, l
+	}
+	l++ // range is (l, r]
+	k := bits.Len32(uint32(r-l+1)) - 1
+	m := x.min[k]
+	return x.from[x.shallower(m[l], m[r-(1<<uint(k))+1])]
+}
+
+// Depth returns the depth of node n, the number of arcs from its root.
+//
+// It returns -1 for nodes not in the forest.
+func (x *LCAIndex) Depth(n NI) int {
+	if x.root[n] < 0 {
+		return -1
+	}
+	return int(x.depth[n])
+}
+
+// Distance returns the number of arcs on the tree path between a and b.
+//
+// It returns -1 if a and b are in different trees.
+func (x *LCAIndex) Distance(a, b NI) int {
+	c := x.CommonStart(a, b)
+	if c < 0 {
+		return -1
+	}
+	return int(x.depth[a] + x.depth[b] - 2*x.depth[c])
+}
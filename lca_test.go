@@ -0,0 +1,81 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLCAIndex() {
+	//   4   5
+	//  /   /
+	// 6   1
+	//    / \
+	//   0   2
+	//  /
+	// 3
+	f := graph.FromList{Paths: []graph.PathEnd{
+		4: {From: -1},
+		6: {From: 4},
+		5: {From: -1},
+		1: {From: 5},
+		0: {From: 1},
+		2: {From: 1},
+		3: {From: 0},
+	}}
+	x := graph.NewLCAIndex(f)
+	fmt.Println(x.CommonStart(2, 3), x.Distance(2, 3))
+	fmt.Println(x.CommonStart(0, 3), x.Distance(0, 3))
+	fmt.Println(x.CommonStart(6, 3), x.Distance(6, 3))
+	// Output:
+	// 1 3
+	// 0 1
+	// -1 -1
+}
+
+func TestLCAIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 100; i++ {
+		// random forest, parents earlier in a random permutation
+		n := 1 + r.Intn(40)
+		pm := r.Perm(n)
+		f := graph.NewFromList(n)
+		for j, v := range pm {
+			f.Paths[v].From = -1
+			if j > 0 && r.Intn(6) > 0 {
+				f.Paths[v].From = graph.NI(pm[r.Intn(j)])
+			}
+		}
+		f.RecalcLeaves()
+		f.RecalcLen()
+		x := graph.NewLCAIndex(f)
+		for a := graph.NI(0); int(a) < n; a++ {
+			if x.Depth(a) != f.Paths[a].Len-1 {
+				t.Fatal("depth", a)
+			}
+			for b := graph.NI(0); int(b) < n; b++ {
+				if got, want := x.CommonStart(a, b), f.CommonStart(a, b); got != want {
+					t.Fatalf("CommonStart(%d, %d) = %d, want %d", a, b, got, want)
+				}
+			}
+		}
+	}
+	// nodes on and under a cycle are not in the forest
+	f := graph.FromList{Paths: []graph.PathEnd{
+		0: {From: -1},
+		1: {From: 0},
+		2: {From: 3},
+		3: {From: 2},
+		4: {From: 3},
+	}}
+	x := graph.NewLCAIndex(f)
+	if x.CommonStart(0, 1) != 0 || x.CommonStart(2, 4) != -1 ||
+		x.Depth(4) != -1 {
+		t.Fatal("cyclic FromList")
+	}
+}
@@ -0,0 +1,604 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "math"
+
+// matching.go has weighted matching support for other algorithms.
+
+// matchEdge is an undirected weighted edge for maxWeightMatching.
+type matchEdge struct {
+	n1, n2 int
+	w      float64
+}
+
+// minWeightPerfectMatching computes a minimum weight perfect matching of
+// nodes 0..n-1 over the given edges.
+//
+// The result mate is as for maxWeightMatching.  If no perfect matching
+// exists, some nodes will be left unmatched with mate -1.
+func minWeightPerfectMatching(n int, edges []matchEdge) (mate []int) {
+	// Any maximum cardinality matching of a graph with a perfect matching
+	// is perfect, and all have the same number of edges.  Maximizing
+	// c - w over them then minimizes w.
+	c := 0.
+	for _, e := range edges {
+		c = math.Max(c, e.w)
+	}
+	c++
+	neg := make([]matchEdge, len(edges))
+	for i, e := range edges {
+		neg[i] = matchEdge{e.n1, e.n2, c - e.w}
+	}
+	return maxWeightMatching(n, neg, true)
+}
+
+// maxWeightMatching computes a maximum weight matching of nodes 0..n-1
+// over the given edges.
+//
+// If maxCard is true, the result is a maximum weight matching among all
+// matchings of maximum cardinality.
+//
+// The result mate has mate[n] = the node matched to n, or -1 if n is
+// unmatched.
+//
+// The algorithm is Edmonds' blossom algorithm with dual variables,
+// O(n³) time.
+//
+// Ref: "Efficient Algorithms for Finding Maximum Matching in Graphs",
+// Zvi Galil, ACM Computing Surveys (1986).  The structure follows Joris van
+// Rantwijk's mwmatching.py.
+func maxWeightMatching(n int, edges []matchEdge, maxCard bool) (mate []int) {
+	mate = make([]int, n)
+	for i := range mate {
+		mate[i] = -1
+	}
+	if len(edges) == 0 {
+		return
+	}
+	m := newWMatch(n, edges)
+	m.run(maxCard)
+	for v, p := range m.mate {
+		if p >= 0 {
+			mate[v] = m.endpoint[p]
+		}
+	}
+	return
+}
+
+// wMatch holds state for maxWeightMatching.
+//
+// Edge k has endpoints 2k and 2k+1.  Nodes are 0..n-1, blossoms are
+// n..2n-1.  Labels are 0 for free, 1 for S, 2 for T.  During the search
+// mate[v] is the remote endpoint of v's matched edge, or -1.
+type wMatch struct {
+	n         int
+	edges     []matchEdge
+	endpoint  []int
+	neighbend [][]int // endpoints of edges incident to each node
+	mate      []int
+	label     []int
+	labelEnd  []int
+	inBlossom []int   // top level blossom containing each node
+	parent    []int   // immediate parent blossom, or -1
+	childs    [][]int // sub-blossoms in cyclic order, starting with base
+	base      []int
+	endps     [][]int // endpoints connecting childs
+	bestEdge  []int
+	bestList  [][]int // least slack edges to neighboring S-blossoms
+	unused    []int
+	dual      []float64
+	allow     []bool
+	queue     []int
+}
+
+func newWMatch(n int, edges []matchEdge) *wMatch {
+	m := &wMatch{
+		n:         n,
+		edges:     edges,
+		endpoint:  make([]int, 2*len(edges)),
+		neighbend: make([][]int, n),
+		mate:      make([]int, n),
+		label:     make([]int, 2*n),
+		labelEnd:  make([]int, 2*n),
+		inBlossom: make([]int, n),
+		parent:    make([]int, 2*n),
+		childs:    make([][]int, 2*n),
+		base:      make([]int, 2*n),
+		endps:     make([][]int, 2*n),
+		bestEdge:  make([]int, 2*n),
+		bestList:  make([][]int, 2*n),
+		dual:      make([]float64, 2*n),
+		allow:     make([]bool, len(edges)),
+	}
+	maxW := 0.
+	for k, e := range edges {
+		m.endpoint[2*k] = e.n1
+		m.endpoint[2*k+1] = e.n2
+		m.neighbend[e.n1] = append(m.neighbend[e.n1], 2*k+1)
+		m.neighbend[e.n2] = append(m.neighbend[e.n2], 2*k)
+		maxW = math.Max(maxW, e.w)
+	}
+	for i := range m.mate {
+		m.mate[i] = -1
+		m.inBlossom[i] = i
+		m.base[i] = i
+		m.base[n+i] = -1
+		m.dual[i] = maxW
+		m.unused = append(m.unused, n+i)
+	}
+	for i := range m.labelEnd {
+		m.labelEnd[i] = -1
+		m.parent[i] = -1
+		m.bestEdge[i] = -1
+	}
+	return m
+}
+
+func (m *wMatch) slack(k int) float64 {
+	e := m.edges[k]
+	return m.dual[e.n1] + m.dual[e.n2] - 2*e.w
+}
+
+// leaves appends the nodes contained in blossom b.
+func (m *wMatch) leaves(b int, out []int) []int {
+	if b < m.n {
+		return append(out, b)
+	}
+	for _, t := range m.childs[b] {
+		out = m.leaves(t, out)
+	}
+	return out
+}
+
+// at indexes s, with negative i counting from the end.
+func at(s []int, i int) int {
+	if i < 0 {
+		i += len(s)
+	}
+	return s[i]
+}
+
+func indexOf(s []int, x int) int {
+	for i, y := range s {
+		if y == x {
+			return i
+		}
+	}
+	return -1
+}
+
+// assignLabel labels the top level blossom of w with t, reached through
+// endpoint p.
+func (m *wMatch) assignLabel(w, t, p int) {
+	b := m.inBlossom[w]
+	m.label[w], m.label[b] = t, t
+	m.labelEnd[w], m.labelEnd[b] = p, p
+	m.bestEdge[w], m.bestEdge[b] = -1, -1
+	if t == 1 {
+		m.queue = m.leaves(b, m.queue)
+		return
+	}
+	base := m.base[b]
+	m.assignLabel(m.endpoint[m.mate[base]], 1, m.mate[base]^1)
+}
+
+// scanBlossom traces back from v and w to find a new blossom, returning
+// its base, or -1 if v and w are in different trees (an augmenting path).
+func (m *wMatch) scanBlossom(v, w int) int {
+	var path []int
+	base := -1
+	for v != -1 || w != -1 {
+		b := m.inBlossom[v]
+		if m.label[b]&4 != 0 {
+			base = m.base[b]
+			break
+		}
+		path = append(path, b)
+		m.label[b] = 5
+		if m.labelEnd[b] == -1 {
+			v = -1
+		} else {
+			v = m.endpoint[m.labelEnd[b]]
+			b = m.inBlossom[v]
+			v = m.endpoint[m.labelEnd[b]]
+		}
+		if w != -1 {
+			v, w = w, v
+		}
+	}
+	for _, b := range path {
+		m.label[b] = 1
+	}
+	return base
+}
+
+// addBlossom constructs a new blossom with the given base, closed by
+// edge k.
+func (m *wMatch) addBlossom(base, k int) {
+	v, w := m.edges[k].n1, m.edges[k].n2
+	bb := m.inBlossom[base]
+	bv := m.inBlossom[v]
+	bw := m.inBlossom[w]
+	b := m.unused[len(m.unused)-1]
+	m.unused = m.unused[:len(m.unused)-1]
+	m.base[b] = base
+	m.parent[b] = -1
+	m.parent[bb] = b
+	var path, endps []int
+	for bv != bb {
+		m.parent[bv] = b
+		path = append(path, bv)
+		endps = append(endps, m.labelEnd[bv])
+		v = m.endpoint[m.labelEnd[bv]]
+		bv = m.inBlossom[v]
+	}
+	path = append(path, bb)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	for i, j := 0, len(endps)-1; i < j; i, j = i+1, j-1 {
+		endps[i], endps[j] = endps[j], endps[i]
+	}
+	endps = append(endps, 2*k)
+	for bw != bb {
+		m.parent[bw] = b
+		path = append(path, bw)
+		endps = append(endps, m.labelEnd[bw]^1)
+		w = m.endpoint[m.labelEnd[bw]]
+		bw = m.inBlossom[w]
+	}
+	m.childs[b] = path
+	m.endps[b] = endps
+	m.label[b] = 1
+	m.labelEnd[b] = m.labelEnd[bb]
+	m.dual[b] = 0
+	for _, v := range m.leaves(b, nil) {
+		if m.label[m.inBlossom[v]] == 2 {
+			// former T-nodes become S-nodes and must be scanned
+			m.queue = append(m.queue, v)
+		}
+		m.inBlossom[v] = b
+	}
+	// compute least slack edges to neighboring S-blossoms
+	bestTo := make([]int, 2*m.n)
+	for i := range bestTo {
+		bestTo[i] = -1
+	}
+	consider := func(k int) {
+		j := m.edges[k].n2
+		if m.inBlossom[j] == b {
+			j = m.edges[k].n1
+		}
+		if bj := m.inBlossom[j]; bj != b && m.label[bj] == 1 &&
+			(bestTo[bj] == -1 || m.slack(k) < m.slack(bestTo[bj])) {
+			bestTo[bj] = k
+		}
+	}
+	for _, bv := range path {
+		if m.bestList[bv] == nil {
+			for _, v := range m.leaves(bv, nil) {
+				for _, p := range m.neighbend[v] {
+					consider(p / 2)
+				}
+			}
+		} else {
+			for _, k := range m.bestList[bv] {
+				consider(k)
+			}
+		}
+		m.bestList[bv] = nil
+		m.bestEdge[bv] = -1
+	}
+	bl := []int{}
+	m.bestEdge[b] = -1
+	for _, k := range bestTo {
+		if k == -1 {
+			continue
+		}
+		bl = append(bl, k)
+		if m.bestEdge[b] == -1 || m.slack(k) < m.slack(m.bestEdge[b]) {
+			m.bestEdge[b] = k
+		}
+	}
+	m.bestList[b] = bl
+}
+
+// expandBlossom returns the sub-blossoms of b to top level.
+//
+// If endStage is false, b is a T-blossom being expanded during a stage
+// and labels are reassigned along the even path through b.
+func (m *wMatch) expandBlossom(b int, endStage bool) {
+	for _, s := range m.childs[b] {
+		m.parent[s] = -1
+		switch {
+		case s < m.n:
+			m.inBlossom[s] = s
+		case endStage && m.dual[s] == 0:
+			m.expandBlossom(s, endStage)
+		default:
+			for _, v := range m.leaves(s, nil) {
+				m.inBlossom[v] = s
+			}
+		}
+	}
+	if !endStage && m.label[b] == 2 {
+		childs, endps := m.childs[b], m.endps[b]
+		entry := m.inBlossom[m.endpoint[m.labelEnd[b]^1]]
+		j := indexOf(childs, entry)
+		jstep, trick := -1, 1
+		if j&1 != 0 {
+			j -= len(childs)
+			jstep, trick = 1, 0
+		}
+		p := m.labelEnd[b]
+		for j != 0 {
+			// relabel the T-sub-blossom and the S-sub-blossom after it
+			m.label[m.endpoint[p^1]] = 0
+			m.label[m.endpoint[at(endps, j-trick)^trick^1]] = 0
+			m.assignLabel(m.endpoint[p^1], 2, p)
+			m.allow[at(endps, j-trick)/2] = true
+			j += jstep
+			p = at(endps, j-trick) ^ trick
+			m.allow[p/2] = true
+			j += jstep
+		}
+		bv := at(childs, j)
+		m.label[m.endpoint[p^1]], m.label[bv] = 2, 2
+		m.labelEnd[m.endpoint[p^1]], m.labelEnd[bv] = p, p
+		m.bestEdge[bv] = -1
+		j += jstep
+		for at(childs, j) != entry {
+			// sub-blossoms on the odd path may be reachable from outside
+			bv := at(childs, j)
+			j += jstep
+			if m.label[bv] == 1 {
+				continue
+			}
+			for _, v := range m.leaves(bv, nil) {
+				if m.label[v] != 0 {
+					m.label[v] = 0
+					m.label[m.endpoint[m.mate[m.base[bv]]]] = 0
+					m.assignLabel(v, 2, m.labelEnd[v])
+					break
+				}
+			}
+		}
+	}
+	m.label[b], m.labelEnd[b] = -1, -1
+	m.childs[b], m.endps[b] = nil, nil
+	m.base[b] = -1
+	m.bestList[b] = nil
+	m.bestEdge[b] = -1
+	m.unused = append(m.unused, b)
+}
+
+// augmentBlossom swaps matched and unmatched edges along the even path
+// from node v to the base of blossom b, making v the new base.
+func (m *wMatch) augmentBlossom(b, v int) {
+	t := v
+	for m.parent[t] != b {
+		t = m.parent[t]
+	}
+	if t >= m.n {
+		m.augmentBlossom(t, v)
+	}
+	childs, endps := m.childs[b], m.endps[b]
+	i := indexOf(childs, t)
+	j := i
+	jstep, trick := -1, 1
+	if i&1 != 0 {
+		j -= len(childs)
+		jstep, trick = 1, 0
+	}
+	for j != 0 {
+		j += jstep
+		t = at(childs, j)
+		p := at(endps, j-trick) ^ trick
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint[p])
+		}
+		j += jstep
+		t = at(childs, j)
+		if t >= m.n {
+			m.augmentBlossom(t, m.endpoint[p^1])
+		}
+		m.mate[m.endpoint[p]] = p ^ 1
+		m.mate[m.endpoint[p^1]] = p
+	}
+	m.childs[b] = append(append([]int{}, childs[i:]...), childs[:i]...)
+	m.endps[b] = append(append([]int{}, endps[i:]...), endps[:i]...)
+	m.base[b] = m.base[m.childs[b][0]]
+}
+
+// augmentMatching augments along the path through edge k.
+func (m *wMatch) augmentMatching(k int) {
+	e := m.edges[k]
+	for _, sp := range [2][2]int{{e.n1, 2*k + 1}, {e.n2, 2 * k}} {
+		s, p := sp[0], sp[1]
+		for {
+			bs := m.inBlossom[s]
+			if bs >= m.n {
+				m.augmentBlossom(bs, s)
+			}
+			m.mate[s] = p
+			if m.labelEnd[bs] == -1 {
+				break // reached a tree root
+			}
+			t := m.endpoint[m.labelEnd[bs]]
+			bt := m.inBlossom[t]
+			s = m.endpoint[m.labelEnd[bt]]
+			j := m.endpoint[m.labelEnd[bt]^1]
+			if bt >= m.n {
+				m.augmentBlossom(bt, j)
+			}
+			m.mate[j] = m.labelEnd[bt]
+			p = m.labelEnd[bt] ^ 1
+		}
+	}
+}
+
+// run is the main loop, one stage per augmentation.
+func (m *wMatch) run(maxCard bool) {
+	n := m.n
+	for stage := 0; stage < n; stage++ {
+		for i := range m.label {
+			m.label[i] = 0
+			m.bestEdge[i] = -1
+		}
+		for i := n; i < 2*n; i++ {
+			m.bestList[i] = nil
+		}
+		for i := range m.allow {
+			m.allow[i] = false
+		}
+		m.queue = m.queue[:0]
+		for v := 0; v < n; v++ {
+			if m.mate[v] == -1 && m.label[m.inBlossom[v]] == 0 {
+				m.assignLabel(v, 1, -1)
+			}
+		}
+		augmented := false
+		for {
+			for len(m.queue) > 0 && !augmented {
+				v := m.queue[len(m.queue)-1]
+				m.queue = m.queue[:len(m.queue)-1]
+				for _, p := range m.neighbend[v] {
+					k := p / 2
+					w := m.endpoint[p]
+					if m.inBlossom[v] == m.inBlossom[w] {
+						continue
+					}
+					var kslack float64
+					if !m.allow[k] {
+						if kslack = m.slack(k); kslack <= 0 {
+							m.allow[k] = true
+						}
+					}
+					switch {
+					case m.allow[k]:
+						switch {
+						case m.label[m.inBlossom[w]] == 0:
+							m.assignLabel(w, 2, p^1)
+						case m.label[m.inBlossom[w]] == 1:
+							if base := m.scanBlossom(v, w); base >= 0 {
+								m.addBlossom(base, k)
+							} else {
+								m.augmentMatching(k)
+								augmented = true
+							}
+						case m.label[w] == 0:
+							// w is inside a T-blossom but not yet reached
+							m.label[w] = 2
+							m.labelEnd[w] = p ^ 1
+						}
+					case m.label[m.inBlossom[w]] == 1:
+						if b := m.inBlossom[v]; m.bestEdge[b] == -1 ||
+							kslack < m.slack(m.bestEdge[b]) {
+							m.bestEdge[b] = k
+						}
+					case m.label[w] == 0:
+						if m.bestEdge[w] == -1 || kslack < m.slack(m.bestEdge[w]) {
+							m.bestEdge[w] = k
+						}
+					}
+					if augmented {
+						break
+					}
+				}
+			}
+			if augmented {
+				break
+			}
+			// no augmenting path with tight edges; compute a dual update
+			deltaType := -1
+			var delta float64
+			deltaEdge, deltaBlossom := -1, -1
+			if !maxCard {
+				deltaType = 1
+				delta = m.dual[0]
+				for _, d := range m.dual[1:n] {
+					delta = math.Min(delta, d)
+				}
+			}
+			for v := 0; v < n; v++ {
+				if m.label[m.inBlossom[v]] == 0 && m.bestEdge[v] != -1 {
+					if d := m.slack(m.bestEdge[v]); deltaType == -1 || d < delta {
+						delta, deltaType, deltaEdge = d, 2, m.bestEdge[v]
+					}
+				}
+			}
+			for b := 0; b < 2*n; b++ {
+				if m.parent[b] == -1 && m.label[b] == 1 && m.bestEdge[b] != -1 {
+					if d := m.slack(m.bestEdge[b]) / 2; deltaType == -1 || d < delta {
+						delta, deltaType, deltaEdge = d, 3, m.bestEdge[b]
+					}
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if m.base[b] >= 0 && m.parent[b] == -1 && m.label[b] == 2 &&
+					(deltaType == -1 || m.dual[b] < delta) {
+					delta, deltaType, deltaBlossom = m.dual[b], 4, b
+				}
+			}
+			if deltaType == -1 {
+				// maxCard and no further progress possible
+				deltaType = 1
+				delta = m.dual[0]
+				for _, d := range m.dual[1:n] {
+					delta = math.Min(delta, d)
+				}
+				delta = math.Max(0, delta)
+			}
+			for v := 0; v < n; v++ {
+				switch m.label[m.inBlossom[v]] {
+				case 1:
+					m.dual[v] -= delta
+				case 2:
+					m.dual[v] += delta
+				}
+			}
+			for b := n; b < 2*n; b++ {
+				if m.base[b] >= 0 && m.parent[b] == -1 {
+					switch m.label[b] {
+					case 1:
+						m.dual[b] += delta
+					case 2:
+						m.dual[b] -= delta
+					}
+				}
+			}
+			switch deltaType {
+			case 1:
+				// optimum reached
+			case 2:
+				m.allow[deltaEdge] = true
+				i := m.edges[deltaEdge].n1
+				if m.label[m.inBlossom[i]] == 0 {
+					i = m.edges[deltaEdge].n2
+				}
+				m.queue = append(m.queue, i)
+				continue
+			case 3:
+				m.allow[deltaEdge] = true
+				m.queue = append(m.queue, m.edges[deltaEdge].n1)
+				continue
+			case 4:
+				m.expandBlossom(deltaBlossom, false)
+				continue
+			}
+			break
+		}
+		if !augmented {
+			return
+		}
+		// expand S-blossoms with zero dual at end of stage
+		for b := n; b < 2*n; b++ {
+			if m.parent[b] == -1 && m.base[b] >= 0 && m.label[b] == 1 &&
+				m.dual[b] == 0 {
+				m.expandBlossom(b, true)
+			}
+		}
+	}
+}
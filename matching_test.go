@@ -0,0 +1,142 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// matching_test.go tests unexported matching functions directly, so it is
+// in package graph rather than graph_test.
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// bruteMatching returns the best cardinality and weight over all matchings
+// of edges.  If maxCard, matchings of greater cardinality are preferred,
+// then greater weight.  If minPerfect, only perfect matchings of n nodes
+// are considered and the least weight is returned, with card -1 if there
+// is no perfect matching.
+func bruteMatching(n int, edges []matchEdge, maxCard, minPerfect bool) (card int, wt float64) {
+	card, wt = -1, math.Inf(-1)
+	if minPerfect {
+		wt = math.Inf(1)
+	}
+	used := make([]bool, n)
+	var rec func(k, c int, w float64)
+	rec = func(k, c int, w float64) {
+		if k == len(edges) {
+			switch {
+			case minPerfect:
+				if 2*c == n && w < wt {
+					card, wt = c, w
+				}
+			case maxCard:
+				if c > card || c == card && w > wt {
+					card, wt = c, w
+				}
+			case w > wt:
+				card, wt = c, w
+			}
+			return
+		}
+		rec(k+1, c, w)
+		e := edges[k]
+		if !used[e.n1] && !used[e.n2] {
+			used[e.n1], used[e.n2] = true, true
+			rec(k+1, c+1, w+e.w)
+			used[e.n1], used[e.n2] = false, false
+		}
+	}
+	rec(0, 0, 0)
+	return
+}
+
+// checkMatching validates mate as a matching over edges and returns its
+// cardinality and weight.
+func checkMatching(t *testing.T, edges []matchEdge, mate []int) (card int, wt float64) {
+	for i, j := range mate {
+		if j < 0 {
+			continue
+		}
+		if mate[j] != i {
+			t.Fatalf("mate %v not symmetric", mate)
+		}
+		if i > j {
+			continue
+		}
+		card++
+		w := math.Inf(-1)
+		for _, e := range edges {
+			if e.n1 == i && e.n2 == j || e.n1 == j && e.n2 == i {
+				w = math.Max(w, e.w)
+			}
+		}
+		if math.IsInf(w, -1) {
+			t.Fatalf("mate %v matches %d-%d, not an edge", mate, i, j)
+		}
+		wt += w
+	}
+	return
+}
+
+func TestMaxWeightMatching(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 3000; i++ {
+		n := 1 + r.Intn(9)
+		var edges []matchEdge
+		for a := 0; a < n; a++ {
+			for b := a + 1; b < n; b++ {
+				if r.Intn(3) > 0 {
+					// small integer weights give ties, larger ones don't
+					w := float64(r.Intn(4))
+					if i%3 == 0 {
+						w = r.Float64() * 20
+					}
+					edges = append(edges, matchEdge{a, b, w})
+				}
+			}
+		}
+		maxCard := i%2 == 0
+		mate := maxWeightMatching(n, edges, maxCard)
+		if len(mate) != n {
+			t.Fatal("mate length")
+		}
+		c, w := checkMatching(t, edges, mate)
+		wantC, wantW := bruteMatching(n, edges, maxCard, false)
+		if math.Abs(w-wantW) > 1e-9 || maxCard && c != wantC {
+			t.Fatalf("edges %v maxCard %t: card %d weight %v, want %d %v",
+				edges, maxCard, c, w, wantC, wantW)
+		}
+	}
+}
+
+func TestMinWeightPerfectMatching(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 2000; i++ {
+		n := 2 * (1 + r.Intn(4))
+		var edges []matchEdge
+		for a := 0; a < n; a++ {
+			for b := a + 1; b < n; b++ {
+				// complete graphs as used by Christofides, and sparser
+				// graphs that may have no perfect matching.
+				if i%2 == 0 || r.Intn(2) > 0 {
+					edges = append(edges, matchEdge{a, b, r.Float64() * 20})
+				}
+			}
+		}
+		mate := minWeightPerfectMatching(n, edges)
+		c, w := checkMatching(t, edges, mate)
+		wantC, wantW := bruteMatching(n, edges, false, true)
+		if wantC < 0 {
+			if 2*c == n {
+				t.Fatalf("edges %v: perfect matching found, none exists", edges)
+			}
+			continue
+		}
+		if 2*c != n || math.Abs(w-wantW) > 1e-9 {
+			t.Fatalf("edges %v: card %d weight %v, want %d %v",
+				edges, c, w, wantC, wantW)
+		}
+	}
+}
@@ -5,80 +5,286 @@ package graph
 
 import (
 	"container/heap"
+	"runtime"
 	"sort"
+	"sync"
+	"sync/atomic"
 
 	"github.com/soniakeys/bits"
 )
 
-type dsElement struct {
-	from NI
-	rank int
-}
-
-type disjointSet struct {
-	set []dsElement
+// Boruvka implements Borůvka's algorithm for constructing a minimum spanning
+// forest on an undirected graph.
+//
+// The forest is returned as an undirected graph.
+//
+// Also returned is a total distance for the returned forest.
+//
+// Argument workers specifies the number of goroutines used.  If workers is
+// 0 or 1, the computation is sequential.  If workers is negative,
+// runtime.GOMAXPROCS(0) goroutines are used.
+//
+// This method is a convenience wrapper for WeightedEdgeList.Boruvka.
+// If you have no need for the input graph as a LabeledUndirected, it may be
+// more efficient to construct a WeightedEdgeList directly.
+func (g LabeledUndirected) Boruvka(w WeightFunc, workers int) (spanningForest LabeledUndirected, dist float64) {
+	return g.WeightedArcsAsEdges(w).Boruvka(workers)
 }
 
-func newDisjointSet(n int) disjointSet {
-	set := make([]dsElement, n)
-	for i := range set {
-		set[i].from = -1
+// Boruvka implements Borůvka's algorithm for constructing a minimum spanning
+// forest on an undirected graph.
+//
+// The algorithm allows parallel edges and loops.  Ties in weight are broken
+// by position in the edge list.  Unlike Kruskal, the receiver edge list
+// is not modified and does not need to be sorted.
+//
+// The forest is returned as an undirected graph, the same as returned by
+// Kruskal.  Also returned is a total distance for the returned forest.
+// In the case of tied weights the forest may differ from that returned by
+// Kruskal but total distance will be the same.
+//
+// Argument workers specifies the number of goroutines used.  If workers is
+// 0 or 1, the computation is sequential.  If workers is negative,
+// runtime.GOMAXPROCS(0) goroutines are used.  With multiple goroutines,
+// the receiver WeightFunc is called concurrently and so must be safe for
+// concurrent use.
+//
+// Memory use beyond the result is a slice of ints the length of the edge
+// list, two slices of ints the order of the graph, and a UnionFind of the
+// order of the graph.
+func (l WeightedEdgeList) Boruvka(workers int) (g LabeledUndirected, dist float64) {
+	if workers < 0 {
+		workers = runtime.GOMAXPROCS(0)
 	}
-	return disjointSet{set}
-}
-
-// return true if disjoint trees were combined.
-// false if x and y were already in the same tree.
-func (ds disjointSet) union(x, y NI) bool {
-	xr := ds.find(x)
-	yr := ds.find(y)
-	if xr == yr {
-		return false
+	if workers < 1 {
+		workers = 1
+	}
+	e := l.Edges
+	w := l.WeightFunc
+	ds := NewUnionFind(l.Order)
+	g.LabeledAdjacencyList = make(LabeledAdjacencyList, l.Order)
+	comp := make([]NI, l.Order)
+	best := make([]int64, l.Order) // index of least edge from each component
+	live := make([]int, len(e))    // edges between distinct components
+	for i := range live {
+		live[i] = i
 	}
-	switch xe, ye := &ds.set[xr], &ds.set[yr]; {
-	case xe.rank < ye.rank:
-		xe.from = yr
-	case xe.rank == ye.rank:
-		xe.rank++
-		fallthrough
-	default:
-		ye.from = xr
+	// less orders edges by weight, then by index.
+	less := func(x, y int64) bool {
+		wx, wy := w(e[x].LI), w(e[y].LI)
+		return wx < wy || wx == wy && x < y
 	}
-	return true
+	propose := func(c NI, x int64) {
+		for {
+			b := atomic.LoadInt64(&best[c])
+			if b >= 0 && !less(x, b) {
+				return
+			}
+			if atomic.CompareAndSwapInt64(&best[c], b, x) {
+				return
+			}
+		}
+	}
+	// scan finds least edges from components for edges of live[lo:hi],
+	// compacting live edges to the start of the range.
+	scan := func(lo, hi int) (n int) {
+		n = lo
+		for _, x := range live[lo:hi] {
+			c1, c2 := comp[e[x].N1], comp[e[x].N2]
+			if c1 == c2 {
+				continue
+			}
+			live[n] = x
+			n++
+			propose(c1, int64(x))
+			propose(c2, int64(x))
+		}
+		return n - lo
+	}
+	nLive := make([]int, workers)
+	for len(live) > 0 {
+		for n := range comp {
+			comp[n] = ds.Find(NI(n))
+			best[n] = -1
+		}
+		// chunk i is live[i*len(live)/workers:(i+1)*len(live)/workers]
+		if workers == 1 {
+			nLive[0] = scan(0, len(live))
+		} else {
+			var wg sync.WaitGroup
+			for i := range nLive {
+				wg.Add(1)
+				go func(i int) {
+					nLive[i] = scan(i*len(live)/workers, (i+1)*len(live)/workers)
+					wg.Done()
+				}(i)
+			}
+			wg.Wait()
+		}
+		// gather compacted chunks
+		n := nLive[0]
+		for i := 1; i < workers; i++ {
+			lo := i * len(live) / workers
+			n += copy(live[n:], live[lo:lo+nLive[i]])
+		}
+		live = live[:n]
+		for _, x := range best {
+			if x >= 0 && ds.Union(e[x].N1, e[x].N2) {
+				g.AddEdge(e[x].Edge, e[x].LI)
+				dist += w(e[x].LI)
+			}
+		}
+	}
+	return
 }
 
-func (ds disjointSet) find(n NI) NI {
-	// fast paths for n == root or from root.
-	// no updates need in these cases.
-	s := ds.set
-	fr := s[n].from
-	if fr < 0 { // n is root
-		return n
+// ChuLiuEdmonds computes a minimum spanning arborescence of a directed
+// graph.
+//
+// An arborescence is a directed spanning tree, with a path from a root
+// to every other node.  ChuLiuEdmonds computes an arborescence of minimum
+// total weight spanning the nodes reachable from the given root.  Arc
+// weights are given by WeightFunc w.  Weights may be negative.  Loops and
+// arcs to root are ignored.  Parallel arcs are allowed.
+//
+// The resulting tree is stored in a FromList, and arc labels optionally
+// stored in a slice, with the same conventions as for SpanTree:
+//
+// If FromList.Paths is not the same length as g, it is allocated and
+// initialized.  This allows a zero value FromList to be passed as f.
+// If FromList.Paths is the same length as g, it is used as is and is not
+// reinitialized.  For nodes spanned, the Path member of the returned
+// FromList is populated with both From and Len values.  The MaxLen member
+// will be updated but not Leaves.
+//
+// The labels slice will be populated only if it is same length as g.
+// Nil can be passed for example if labels are not needed.
+//
+// Returned is the number of nodes spanned, which will be the number of
+// nodes reachable from root, and the total weight of the arborescence.
+//
+// The algorithm is Tarjan's O(m log n) implementation of the Chu-Liu/Edmonds
+// algorithm, using mergeable heaps for incoming arcs and a union-find with
+// rollback for contracted cycles.
+func (g LabeledDirected) ChuLiuEdmonds(root NI, w WeightFunc, f *FromList, labels []LI) (nSpanned int, dist float64) {
+	// Ref: "Finding Optimum Branchings", Robert Tarjan, Networks 7 (1977).
+	// Implementation follows that of the KTH ACM contest template library.
+	a := g.LabeledAdjacencyList
+	var rf FromList
+	nSpanned, _ = g.SpanTree(root, &rf, nil)
+	reached := rf.Paths
+	heaps := make([]*ceNode, len(a))
+	for fr, to := range a {
+		if reached[fr].Len == 0 {
+			continue
+		}
+		for _, to := range to {
+			if to.To != NI(fr) && to.To != root {
+				nd := &ceNode{fr: NI(fr), to: to.To, label: to.Label, wt: w(to.Label)}
+				nd.key = nd.wt
+				heaps[to.To] = ceMerge(heaps[to.To], nd)
+			}
+		}
 	}
-	n, fr = fr, s[fr].from
-	if fr < 0 { // n is from root
-		return n
+	uf := newRollbackUF(len(a))
+	seen := make([]int, len(a))
+	for i := range seen {
+		seen[i] = -1
 	}
-	// otherwise updates needed.
-	// two iterative passes (rather than recursion or stack)
-	// pass 1: find root
-	r := fr
-	for {
-		f := s[r].from
-		if f < 0 {
-			break
+	seen[root] = int(root)
+	path := make([]NI, len(a))
+	q := make([]*ceNode, len(a))
+	in := make([]*ceNode, len(a))
+	type cycle struct {
+		u    NI
+		time int
+		arcs []*ceNode
+	}
+	var cycles []cycle
+	for s := range a {
+		if reached[s].Len == 0 {
+			continue
+		}
+		u := NI(s)
+		qi := 0
+		for seen[u] < 0 {
+			// take the least incoming arc, reducing the weights of the
+			// others by its weight.
+			e := heaps[u].top()
+			heaps[u].delta -= e.key
+			heaps[u] = heaps[u].pop()
+			q[qi] = e
+			path[qi] = u
+			qi++
+			seen[u] = s
+			u = uf.find(e.fr)
+			if seen[u] == s {
+				// found a cycle.  contract it.
+				var cyc *ceNode
+				end := qi
+				time := uf.time()
+				for {
+					qi--
+					v := path[qi]
+					cyc = ceMerge(cyc, heaps[v])
+					if !uf.union(u, v) {
+						break
+					}
+				}
+				u = uf.find(u)
+				heaps[u] = cyc
+				seen[u] = -1
+				cycles = append(cycles, cycle{u, time, append([]*ceNode{}, q[qi:end]...)})
+			}
 		}
-		r = f
+		for _, e := range q[:qi] {
+			in[uf.find(e.to)] = e
+		}
+	}
+	// expand cycles, most recently contracted first
+	for i := len(cycles) - 1; i >= 0; i-- {
+		c := cycles[i]
+		uf.rollback(c.time)
+		e := in[c.u]
+		for _, ce := range c.arcs {
+			in[uf.find(ce.to)] = ce
+		}
+		in[uf.find(e.to)] = e
 	}
-	// pass 2: update froms
-	for {
-		s[n].from = r
-		if fr == r {
-			return r
+	p := f.Paths
+	if len(p) != len(a) {
+		p = make([]PathEnd, len(a))
+		for i := range p {
+			p[i].From = -1
 		}
-		n = fr
-		fr = s[n].from
+		f.Paths = p
 	}
+	p[root] = PathEnd{From: -1, Len: 1}
+	for n, e := range in {
+		if e != nil && reached[n].Len > 0 {
+			p[n] = PathEnd{From: e.fr}
+			if len(labels) == len(p) {
+				labels[n] = e.label
+			}
+			dist += e.wt
+		}
+	}
+	// path lengths
+	var setLen func(NI) int
+	setLen = func(n NI) int {
+		if p[n].Len == 0 {
+			p[n].Len = setLen(p[n].From) + 1
+		}
+		return p[n].Len
+	}
+	for n := range in {
+		if reached[n].Len > 0 {
+			if l := setLen(NI(n)); l > f.MaxLen {
+				f.MaxLen = l
+			}
+		}
+	}
+	return
 }
 
 // Kruskal implements Kruskal's algorithm for constructing a minimum spanning
@@ -130,10 +336,10 @@ func (l WeightedEdgeList) Kruskal() (g LabeledUndirected, dist float64) {
 //
 // Also returned is a total distance for the returned forest.
 func (l WeightedEdgeList) KruskalSorted() (g LabeledUndirected, dist float64) {
-	ds := newDisjointSet(l.Order)
+	ds := NewUnionFind(l.Order)
 	g.LabeledAdjacencyList = make(LabeledAdjacencyList, l.Order)
 	for _, e := range l.Edges {
-		if ds.union(e.N1, e.N2) {
+		if ds.Union(e.N1, e.N2) {
 			g.AddEdge(Edge{e.N1, e.N2}, e.LI)
 			dist += l.WeightFunc(e.LI)
 		}
@@ -252,3 +458,109 @@ func (p *prHeap) Pop() interface{} {
 	*p = r[:last]
 	return r[last]
 }
+
+// ceNode is an arc in a skew heap for ChuLiuEdmonds.
+//
+// Key is the arc weight, wt, reduced by weights of arcs taken.  Delta is a
+// lazy reduction yet to be applied to the subtree.
+type ceNode struct {
+	fr, to    NI
+	label     LI
+	wt        float64
+	key       float64
+	delta     float64
+	left, rgt *ceNode
+}
+
+func (nd *ceNode) prop() {
+	nd.key += nd.delta
+	if nd.left != nil {
+		nd.left.delta += nd.delta
+	}
+	if nd.rgt != nil {
+		nd.rgt.delta += nd.delta
+	}
+	nd.delta = 0
+}
+
+func (nd *ceNode) top() *ceNode {
+	nd.prop()
+	return nd
+}
+
+// pop returns the heap with the top node removed.
+func (nd *ceNode) pop() *ceNode {
+	nd.prop()
+	return ceMerge(nd.left, nd.rgt)
+}
+
+func ceMerge(a, b *ceNode) *ceNode {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	a.prop()
+	b.prop()
+	if a.key > b.key {
+		a, b = b, a
+	}
+	a.left, a.rgt = ceMerge(b, a.rgt), a.left
+	return a
+}
+
+// rollbackUF is a union-find without path compression, allowing unions
+// to be undone.
+type rollbackUF struct {
+	e  []int // parent, or for roots, -size
+	st []ufChange
+}
+
+type ufChange struct {
+	x, e int
+}
+
+func newRollbackUF(n int) *rollbackUF {
+	e := make([]int, n)
+	for i := range e {
+		e[i] = -1
+	}
+	return &rollbackUF{e: e}
+}
+
+func (uf *rollbackUF) find(n NI) NI {
+	for uf.e[n] >= 0 {
+		n = NI(uf.e[n])
+	}
+	return n
+}
+
+// time returns a value that can be passed to rollback.
+func (uf *rollbackUF) time() int {
+	return len(uf.st)
+}
+
+// rollback undoes unions done since time t.
+func (uf *rollbackUF) rollback(t int) {
+	for i := len(uf.st) - 1; i >= t; i-- {
+		uf.e[uf.st[i].x] = uf.st[i].e
+	}
+	uf.st = uf.st[:t]
+}
+
+// union returns true if disjoint sets were combined.
+// false if x and y were already in the same set.
+func (uf *rollbackUF) union(x, y NI) bool {
+	x, y = uf.find(x), uf.find(y)
+	if x == y {
+		return false
+	}
+	if uf.e[x] > uf.e[y] {
+		x, y = y, x
+	}
+	uf.st = append(uf.st, ufChange{int(x), uf.e[x]}, ufChange{int(y), uf.e[y]})
+	uf.e[x] += uf.e[y]
+	uf.e[y] = int(x)
+	return true
+}
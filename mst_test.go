@@ -5,12 +5,125 @@ package graph_test
 
 import (
 	"fmt"
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/soniakeys/bits"
 	"github.com/soniakeys/graph"
 )
 
+func ExampleLabeledUndirected_Boruvka() {
+	//       (10)
+	//     0------4----\
+	//     |     /|     \(70)
+	// (30)| (40) |(60)  \
+	//     |/     |      |
+	//     1------2------3
+	//       (50)   (20)
+	w := func(l graph.LI) float64 { return float64(l) }
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 30)
+	g.AddEdge(graph.Edge{0, 4}, 10)
+	g.AddEdge(graph.Edge{1, 2}, 50)
+	g.AddEdge(graph.Edge{1, 4}, 40)
+	g.AddEdge(graph.Edge{2, 3}, 20)
+	g.AddEdge(graph.Edge{2, 4}, 60)
+	g.AddEdge(graph.Edge{3, 4}, 70)
+
+	t, dist := g.Boruvka(w, 0)
+
+	fmt.Println("spanning tree as undirected graph:")
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total distance: ", dist)
+	// Output:
+	// spanning tree as undirected graph:
+	// 0 [{4 10} {1 30}]
+	// 1 [{0 30} {2 50}]
+	// 2 [{3 20} {1 50}]
+	// 3 [{2 20}]
+	// 4 [{0 10}]
+	// total distance:  110
+}
+
+func ExampleWeightedEdgeList_Boruvka() {
+	//       (10)
+	//     0------4----\
+	//     |     /|     \(70)
+	// (30)| (40) |(60)  \
+	//     |/     |      |
+	//     1------2------3
+	//       (50)   (20)
+	l := graph.WeightedEdgeList{
+		Order:      5,
+		WeightFunc: func(l graph.LI) float64 { return float64(l) },
+		Edges: []graph.LabeledEdge{
+			{graph.Edge{0, 1}, 30},
+			{graph.Edge{0, 4}, 10},
+			{graph.Edge{1, 2}, 50},
+			{graph.Edge{1, 4}, 40},
+			{graph.Edge{2, 3}, 20},
+			{graph.Edge{2, 4}, 60},
+			{graph.Edge{3, 4}, 70},
+		},
+	}
+	// parallel mode
+	t, dist := l.Boruvka(4)
+
+	fmt.Println("spanning tree as undirected graph:")
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	fmt.Println("total distance: ", dist)
+	// Output:
+	// spanning tree as undirected graph:
+	// 0 [{4 10} {1 30}]
+	// 1 [{0 30} {2 50}]
+	// 2 [{3 20} {1 50}]
+	// 3 [{2 20}]
+	// 4 [{0 10}]
+	// total distance:  110
+}
+
+func ExampleLabeledDirected_ChuLiuEdmonds() {
+	//        (10)      (1)
+	//     0------>1<-------2
+	//     |       |\       ^
+	//  (4)|    (2)| \(8)   |(3)
+	//     v       v  \     |
+	//     3------>4   ---->5
+	//        (9)
+	w := func(l graph.LI) float64 { return float64(l) }
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{1, 10}, {3, 4}},
+		1: {{4, 2}, {5, 8}},
+		2: {{1, 1}},
+		3: {{4, 9}},
+		5: {{2, 3}},
+	}}
+	var f graph.FromList
+	labels := make([]graph.LI, g.Order())
+	ns, dist := g.ChuLiuEdmonds(0, w, &f, labels)
+	fmt.Println("nodes spanned:", ns)
+	fmt.Println("total weight: ", dist)
+	fmt.Println("node  from  label")
+	for n, e := range f.Paths {
+		fmt.Printf("%d  %4d  %5d\n", n, e.From, labels[n])
+	}
+	// Output:
+	// nodes spanned: 6
+	// total weight:  27
+	// node  from  label
+	// 0    -1      0
+	// 1     0     10
+	// 2     5      3
+	// 3     0      4
+	// 4     1      2
+	// 5     1      8
+}
+
 func ExampleLabeledUndirected_Kruskal() {
 	//       (10)
 	//     0------4----\
@@ -257,3 +370,113 @@ func TestPrim100(t *testing.T) {
 		}
 	}
 }
+
+func TestChuLiuEdmonds(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	w := func(l graph.LI) float64 { return float64(l) }
+	for i := 0; i < 200; i++ {
+		n := 1 + rnd.Intn(7)
+		var g graph.LabeledDirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for j := rnd.Intn(3 * n); j > 0; j-- {
+			fr := rnd.Intn(n)
+			g.LabeledAdjacencyList[fr] = append(g.LabeledAdjacencyList[fr],
+				graph.Half{graph.NI(rnd.Intn(n)), graph.LI(rnd.Intn(21) - 5)})
+		}
+		var f graph.FromList
+		labels := make([]graph.LI, n)
+		ns, dist := g.ChuLiuEdmonds(0, w, &f, labels)
+		var sf graph.FromList
+		if want, _ := g.SpanTree(0, &sf, nil); ns != want {
+			t.Fatalf("nSpanned = %d, want %d", ns, want)
+		}
+		// validate tree
+		sum := 0.
+		for x, e := range f.Paths {
+			if sf.Paths[x].Len == 0 || x == 0 {
+				continue
+			}
+			if ok, _ := g.HasArcLabel(e.From, graph.NI(x), labels[x]); !ok {
+				t.Fatalf("arc %d->%d label %d not in graph", e.From, x, labels[x])
+			}
+			if f.Root(graph.NI(x)) != 0 {
+				t.Fatal("node not spanned")
+			}
+			if e.Len != f.Paths[e.From].Len+1 {
+				t.Fatal("invalid Len")
+			}
+			sum += w(labels[x])
+		}
+		if sum != dist {
+			t.Fatalf("dist = %g, tree weight %g", dist, sum)
+		}
+		// brute force: try all choices of an incoming arc for each node.
+		best := math.Inf(1)
+		par := make([]graph.NI, n)
+		var try func(x int, d float64)
+		try = func(x int, d float64) {
+			if x == n {
+				for y := range par {
+					// check each node reaches the root
+					z, k := graph.NI(y), 0
+					for ; z > 0 && k <= n; k++ {
+						z = par[z]
+					}
+					if sf.Paths[y].Len > 0 && z != 0 {
+						return
+					}
+				}
+				if d < best {
+					best = d
+				}
+				return
+			}
+			if x == 0 || sf.Paths[x].Len == 0 {
+				try(x+1, d)
+				return
+			}
+			for fr, to := range g.LabeledAdjacencyList {
+				for _, to := range to {
+					if to.To == graph.NI(x) && fr != x && sf.Paths[fr].Len > 0 {
+						par[x] = graph.NI(fr)
+						try(x+1, d+w(to.Label))
+					}
+				}
+			}
+		}
+		try(0, 0)
+		if dist != best {
+			t.Fatalf("dist = %g, want %g", dist, best)
+		}
+	}
+}
+
+func TestBoruvka(t *testing.T) {
+	rnd := rand.New(rand.NewSource(5))
+	for i := 0; i < 50; i++ {
+		g, _, wt := graph.LabeledGeometric(200, .1, rnd)
+		// integer weights give many ties
+		w := func(l graph.LI) float64 { return math.Floor(wt[l] * 20) }
+		_, want := g.Kruskal(w)
+		for _, workers := range []int{0, 3, -1} {
+			f, dist := g.Boruvka(w, workers)
+			if dist != want {
+				t.Fatalf("workers %d: dist = %g, want %g", workers, dist, want)
+			}
+			// same components as g
+			gi, gc := g.ConnectedComponentInts()
+			fi, fc := f.ConnectedComponentInts()
+			if fc != gc {
+				t.Fatalf("workers %d: %d components, want %d", workers, fc, gc)
+			}
+			for n := range gi {
+				if gi[n] != fi[n] {
+					t.Fatal("components differ")
+				}
+			}
+			if _, _, _, simple := f.FromList(); !simple {
+				t.Fatal("not a simple forest")
+			}
+		}
+	}
+}
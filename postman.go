@@ -0,0 +1,158 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// postman.go has Chinese postman, or route inspection, algorithms.
+//
+// A postman walk is a closed walk traversing every edge or arc at least once.
+// A minimum weight walk is found by duplicating edges or arcs of a minimum
+// total weight so that the graph becomes Eulerian, then finding an Eulerian
+// cycle.
+//
+// The walk is returned in the format of EulerianCycle.  The first element
+// represents only the start node, node 0.  The remaining elements represent
+// the half arcs of the walk, with labels identifying the edges or arcs
+// traversed.  The cost is the sum of weights of the arcs traversed.
+//
+// Weights must be non-negative.
+
+// ChinesePostman finds a minimum weight closed walk traversing every edge
+// of an undirected graph.
+//
+// Graph g must be connected.  If not, an error is returned.
+//
+// Odd degree nodes are paired by a minimum weight perfect matching on
+// shortest path distances, and edges along the shortest paths between
+// paired nodes are duplicated.
+//
+// See postman.go for the result format.
+//
+// Ref: "Matching, Euler tours and the Chinese postman", Jack Edmonds and
+// Ellis L. Johnson, Mathematical Programming (1973).
+func (g LabeledUndirected) ChinesePostman(w WeightFunc) (walk []Half, cost float64, err error) {
+	a := g.LabeledAdjacencyList
+	if len(a) == 0 {
+		return nil, 0, nil
+	}
+	if !g.IsConnected() {
+		return nil, math.Inf(1), errors.New("not connected")
+	}
+	var odd []NI
+	for n := range a {
+		if g.Degree(NI(n))%2 == 1 {
+			odd = append(odd, NI(n))
+		}
+	}
+	c, _ := g.Copy()
+	if len(odd) > 0 {
+		// shortest paths from each odd node
+		from := make([]FromList, len(odd))
+		labels := make([][]LI, len(odd))
+		var edges []matchEdge
+		for i, n := range odd {
+			var dist []float64
+			from[i], labels[i], dist, _ = a.Dijkstra(n, -1, w)
+			for j := 0; j < i; j++ {
+				edges = append(edges, matchEdge{j, i, dist[odd[j]]})
+			}
+		}
+		mate := minWeightPerfectMatching(len(odd), edges)
+		for i, j := range mate {
+			if j >= i {
+				continue
+			}
+			// duplicate edges of path from odd[i] to odd[j]
+			p := from[i].Paths
+			for n := odd[j]; n != odd[i]; n = p[n].From {
+				c.AddEdge(Edge{p[n].From, n}, labels[i][n])
+			}
+		}
+	}
+	if walk, err = c.EulerianCycleD(c.Size()); err != nil {
+		return nil, math.Inf(1), err
+	}
+	return walk, walkCost(walk, w), nil
+}
+
+// ChinesePostman finds a minimum weight closed walk traversing every arc
+// of a directed graph.
+//
+// Graph g must be strongly connected.  If not, an error is returned.
+//
+// Arcs to duplicate are found with a minimum cost flow from nodes with
+// excess in-degree to nodes with excess out-degree.
+//
+// See postman.go for the result format.
+//
+// Ref: "Matching, Euler tours and the Chinese postman", Jack Edmonds and
+// Ellis L. Johnson, Mathematical Programming (1973).
+func (g LabeledDirected) ChinesePostman(w WeightFunc) (walk []Half, cost float64, err error) {
+	a := g.LabeledAdjacencyList
+	if len(a) == 0 {
+		return nil, 0, nil
+	}
+	nc := 0
+	g.StronglyConnectedComponents(func([]NI) bool {
+		nc++
+		return true
+	})
+	if nc > 1 {
+		return nil, math.Inf(1), errors.New("not strongly connected")
+	}
+	// balance is out-degree - in-degree
+	bal := make([]int, len(a))
+	for fr, to := range a {
+		bal[fr] += len(to)
+		for _, to := range to {
+			bal[to.To]--
+		}
+	}
+	s := NI(len(a))
+	t := s + 1
+	f := newFlowNet(len(a) + 2)
+	for n, b := range bal {
+		switch {
+		case b < 0:
+			f.addCostArc(s, NI(n), float64(-b), 0)
+		case b > 0:
+			f.addCostArc(NI(n), t, float64(b), 0)
+		}
+	}
+	x0 := len(f.to)
+	for fr, to := range a {
+		for _, to := range to {
+			f.addCostArc(NI(fr), to.To, math.Inf(1), w(to.Label))
+		}
+	}
+	f.minCostFlow(s, t, math.Inf(1))
+	// duplicate arcs carrying flow
+	c, m := g.Copy()
+	x := x0
+	for fr, to := range a {
+		for _, to := range to {
+			for k := f.cap[x^1]; k > 0; k-- {
+				c.LabeledAdjacencyList[fr] = append(c.LabeledAdjacencyList[fr], to)
+				m++
+			}
+			x += 2
+		}
+	}
+	if walk, err = c.EulerianCycleD(m); err != nil {
+		return nil, math.Inf(1), err
+	}
+	return walk, walkCost(walk, w), nil
+}
+
+// walkCost sums weights of a walk in EulerianCycle format.
+func walkCost(walk []Half, w WeightFunc) (cost float64) {
+	for _, h := range walk[1:] {
+		cost += w(h.Label)
+	}
+	return
+}
@@ -0,0 +1,234 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledUndirected_ChinesePostman() {
+	//      (1)
+	//   0-------1
+	//   |     / |
+	//   |(2) /  |(1)
+	//   |   /(3)|
+	//   |  /    |
+	//   3-------2
+	//      (2)
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 1)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{2, 3}, 2)
+	g.AddEdge(graph.Edge{3, 0}, 2)
+	g.AddEdge(graph.Edge{1, 3}, 3)
+	w := func(l graph.LI) float64 { return float64(l) }
+	walk, cost, err := g.ChinesePostman(w)
+	fmt.Println(walk)
+	fmt.Println(cost, err)
+	// Output:
+	// [{0 -1} {1 1} {3 3} {2 2} {1 1} {3 3} {0 2}]
+	// 12 <nil>
+}
+
+func ExampleLabeledDirected_ChinesePostman() {
+	//    0 ---> 1
+	//    ^ \    |
+	//    |  \   |
+	//    |   v  v
+	//    3 <--- 2
+	g := graph.LabeledDirected{graph.LabeledAdjacencyList{
+		0: {{To: 1, Label: 1}, {To: 2, Label: 4}},
+		1: {{To: 2, Label: 1}},
+		2: {{To: 3, Label: 1}},
+		3: {{To: 0, Label: 1}},
+	}}
+	w := func(l graph.LI) float64 { return float64(l) }
+	walk, cost, err := g.ChinesePostman(w)
+	fmt.Println(walk)
+	fmt.Println(cost, err)
+	// Output:
+	// [{0 -1} {1 1} {2 1} {3 1} {0 1} {2 4} {3 1} {0 1}]
+	// 10 <nil>
+}
+
+// postmanCheck validates a postman walk on g, returning the walk cost.
+func postmanCheck(t *testing.T, g graph.LabeledAdjacencyList, undir bool, walk []graph.Half, w graph.WeightFunc) float64 {
+	type arc struct {
+		fr, to graph.NI
+		l      graph.LI
+	}
+	key := func(fr, to graph.NI, l graph.LI) arc {
+		if undir && fr > to {
+			fr, to = to, fr
+		}
+		return arc{fr, to, l}
+	}
+	seen := map[arc]bool{}
+	cost := 0.
+	fr := walk[0].To
+	for _, h := range walk[1:] {
+		if ok, _ := g.HasArcLabel(fr, h.To, h.Label); !ok {
+			t.Fatalf("walk %v uses non-arc %d->%v", walk, fr, h)
+		}
+		seen[key(fr, h.To, h.Label)] = true
+		cost += w(h.Label)
+		fr = h.To
+	}
+	if fr != walk[0].To {
+		t.Fatal("walk not closed")
+	}
+	for fr, to := range g {
+		for _, h := range to {
+			if !seen[key(graph.NI(fr), h.To, h.Label)] {
+				t.Fatalf("walk %v misses arc %d->%v", walk, fr, h)
+			}
+		}
+	}
+	return cost
+}
+
+func TestChinesePostman(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(7)
+		undir := i%2 == 0
+		var a graph.LabeledAdjacencyList
+		if undir {
+			u := graph.GnmUndirected(n, r.Intn(n*(n-1)/2+1), r)
+			var g graph.LabeledUndirected
+			g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+			u.Edges(func(e graph.Edge) {
+				g.AddEdge(e, graph.LI(r.Intn(10)))
+			})
+			a = g.LabeledAdjacencyList
+		} else {
+			d := graph.GnmDirected(n, r.Intn(n*(n-1)+1), r)
+			a = make(graph.LabeledAdjacencyList, n)
+			for fr, to := range d.AdjacencyList {
+				for _, to := range to {
+					a[fr] = append(a[fr], graph.Half{to, graph.LI(r.Intn(10))})
+				}
+			}
+		}
+		w := func(l graph.LI) float64 { return float64(l) }
+		var walk []graph.Half
+		var cost float64
+		var err error
+		if undir {
+			walk, cost, err = graph.LabeledUndirected{a}.ChinesePostman(w)
+		} else {
+			walk, cost, err = graph.LabeledDirected{a}.ChinesePostman(w)
+		}
+		// independent optimum: total weight plus a minimum cost pairing of
+		// unbalanced nodes by shortest path distance
+		d := a.DistanceMatrix(w)
+		d.FloydWarshall()
+		total := 0.
+		var src, dst []graph.NI
+		for fr, to := range a {
+			for _, h := range to {
+				if !undir || graph.NI(fr) <= h.To {
+					total += w(h.Label)
+				}
+				if !undir {
+					src = append(src, h.To) // in-degree
+					dst = append(dst, graph.NI(fr))
+				}
+			}
+		}
+		connected := true
+		for _, di := range d {
+			for _, dij := range di {
+				if math.IsInf(dij, 1) {
+					connected = false
+				}
+			}
+		}
+		if !connected {
+			if err == nil {
+				t.Fatal("no error for disconnected graph")
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c := postmanCheck(t, a, undir, walk, w); c != cost {
+			t.Fatal("wrong cost")
+		}
+		var extra float64
+		if undir {
+			var odd []graph.NI
+			for n := range a {
+				if (graph.LabeledUndirected{a}).Degree(graph.NI(n))%2 == 1 {
+					odd = append(odd, graph.NI(n))
+				}
+			}
+			extra = postmanPairing(d, odd, odd, true)
+		} else {
+			// cancel matching in and out arcs to leave excess units
+			bal := make([]int, n)
+			for _, v := range src {
+				bal[v]++
+			}
+			for _, v := range dst {
+				bal[v]--
+			}
+			src, dst = nil, nil
+			for v, b := range bal {
+				for ; b > 0; b-- {
+					src = append(src, graph.NI(v))
+				}
+				for ; b < 0; b++ {
+					dst = append(dst, graph.NI(v))
+				}
+			}
+			extra = postmanPairing(d, src, dst, false)
+		}
+		if math.Abs(cost-(total+extra)) > 1e-9 {
+			t.Fatalf("cost %v, want %v", cost, total+extra)
+		}
+	}
+}
+
+// postmanPairing returns the minimum total distance of a pairing of src
+// with dst by brute force.  If undir, src and dst are the same list of
+// nodes to be paired among themselves.
+func postmanPairing(d graph.DistanceMatrix, src, dst []graph.NI, undir bool) float64 {
+	best := math.Inf(1)
+	if undir {
+		var pair func([]graph.NI, float64)
+		pair = func(s []graph.NI, c float64) {
+			if len(s) == 0 {
+				best = math.Min(best, c)
+				return
+			}
+			for i := 1; i < len(s); i++ {
+				r := append([]graph.NI{}, s[1:i]...)
+				pair(append(r, s[i+1:]...), c+d[s[0]][s[i]])
+			}
+		}
+		pair(src, 0)
+		return best
+	}
+	var perm func(int, float64)
+	perm = func(k int, c float64) {
+		if k == len(dst) {
+			best = math.Min(best, c)
+			return
+		}
+		for i := k; i < len(dst); i++ {
+			dst[k], dst[i] = dst[i], dst[k]
+			perm(k+1, c+d[src[k]][dst[k]])
+			dst[k], dst[i] = dst[i], dst[k]
+		}
+	}
+	perm(0, 0)
+	return best
+}
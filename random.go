@@ -706,3 +706,26 @@ ij:
 	}
 	return
 }
+
+// RandomTree constructs a uniformly random tree.
+//
+// The tree has nodes numbered 0 to n-1.  Each of the n^(n-2) distinct trees
+// on these nodes is equally likely.  Construction is from a random Prüfer
+// sequence.  See PruferTree.
+//
+// If Rand r is nil, the rand package default shared source is used.
+func RandomTree(n int, rr *rand.Rand) Undirected {
+	if n < 2 {
+		return Undirected{make(AdjacencyList, n)}
+	}
+	ri := rand.Intn
+	if rr != nil {
+		ri = rr.Intn
+	}
+	code := make([]NI, n-2)
+	for i := range code {
+		code[i] = NI(ri(n))
+	}
+	g, _ := PruferTree(code)
+	return g
+}
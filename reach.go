@@ -0,0 +1,209 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "math/rand"
+
+// reach.go has a reachability index for directed graphs.
+
+// ReachIndex answers reachability queries on a directed graph.
+//
+// The index is built on the condensation of the graph and takes space
+// linear in the size of the condensation, rather than the quadratic space
+// of a TransitiveClosure.  Queries are answered in constant time in most
+// cases, falling back to a search pruned by the index otherwise.
+//
+// Each condensation node is labeled with its position in a topological
+// ordering and with k intervals from randomized depth-first traversals.
+// If a reaches b, the intervals of b are contained in the corresponding
+// intervals of a, so most negative queries are answered without search.
+// The first traversal also gives spanning tree intervals which answer
+// positive queries for tree descendants without search.
+//
+// Ref: "GRAIL: Scalable Reachability Index for Large Graphs", Hilmi Yildirim,
+// Vineet Chaoji, and Mohammed J. Zaki, VLDB (2010).
+//
+// Reachable uses internal scratch space and is not safe for concurrent use.
+type ReachIndex struct {
+	cond  []NI          // condensation node of each graph node
+	cd    AdjacencyList // condensation
+	pos   []int32       // position in topological ordering
+	pre   []int32       // preorder number of first traversal
+	lo    [][]int32     // k intervals [lo, rank]
+	rank  [][]int32     // postorder rank
+	stamp []uint32      // visit marks for search
+	query uint32
+	stack []NI
+}
+
+// NewReachIndex builds a ReachIndex for graph g.
+//
+// Argument k is the number of interval labelings, where values of 2 to 5
+// are typical.  More labelings take more space but answer more negative
+// queries without search.  If k < 1, one labeling is used.
+//
+// Traversals are randomized using r.  If Rand r is nil, the rand package
+// default shared source is used.
+func NewReachIndex(g Directed, k int, r *rand.Rand) *ReachIndex {
+	if k < 1 {
+		k = 1
+	}
+	ri := rand.Intn
+	if r != nil {
+		ri = r.Intn
+	}
+	scc, cd := g.Condensation()
+	x := &ReachIndex{
+		cond:  make([]NI, g.Order()),
+		cd:    cd,
+		pos:   make([]int32, len(cd)),
+		pre:   make([]int32, len(cd)),
+		lo:    make([][]int32, k),
+		rank:  make([][]int32, k),
+		stamp: make([]uint32, len(cd)),
+	}
+	for cn, c := range scc {
+		for _, n := range c {
+			x.cond[n] = NI(cn)
+		}
+	}
+	ordering, _ := Directed{cd}.Topological()
+	for i, n := range ordering {
+		x.pos[n] = int32(i)
+	}
+	// roots are condensation nodes without in-arcs
+	hasIn := make([]bool, len(cd))
+	for _, to := range cd {
+		for _, to := range to {
+			hasIn[to] = true
+		}
+	}
+	var roots []NI
+	for _, n := range ordering {
+		if !hasIn[n] {
+			roots = append(roots, n)
+		}
+	}
+	for i := 0; i < k; i++ {
+		x.label(i, roots, ri)
+	}
+	return x
+}
+
+// label computes the i'th interval labeling by an iterative depth-first
+// traversal.  Roots and arcs are visited starting from random offsets.
+func (x *ReachIndex) label(i int, roots []NI, ri func(int) int) {
+	cd := x.cd
+	lo := make([]int32, len(cd))
+	rank := make([]int32, len(cd))
+	type frame struct {
+		n        NI
+		off, nxt int
+	}
+	var stack []frame
+	var r, p int32 // postorder rank and preorder number
+	push := func(n NI) {
+		if i == 0 {
+			x.pre[n] = p
+			p++
+		}
+		off := 0
+		if len(cd[n]) > 1 {
+			off = ri(len(cd[n]))
+		}
+		lo[n] = -1
+		stack = append(stack, frame{n, off, 0})
+	}
+	ro := 0
+	if len(roots) > 1 {
+		ro = ri(len(roots))
+	}
+	for j := range roots {
+		root := roots[(ro+j)%len(roots)]
+		push(root)
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			to := cd[f.n]
+			if f.nxt < len(to) {
+				c := to[(f.off+f.nxt)%len(to)]
+				f.nxt++
+				if lo[c] == 0 && rank[c] == 0 {
+					push(c)
+				}
+				continue
+			}
+			// finish n.  all successors are finished, as g is a DAG.
+			r++
+			rank[f.n] = r
+			l := r
+			for _, c := range to {
+				if lo[c] < l {
+					l = lo[c]
+				}
+			}
+			lo[f.n] = l
+			stack = stack[:len(stack)-1]
+		}
+	}
+	x.lo[i] = lo
+	x.rank[i] = rank
+}
+
+// contains returns true if all intervals of condensation node a contain
+// the corresponding intervals of b.
+func (x *ReachIndex) contains(a, b NI) bool {
+	for i, lo := range x.lo {
+		rank := x.rank[i]
+		if lo[b] < lo[a] || rank[b] > rank[a] {
+			return false
+		}
+	}
+	return true
+}
+
+// treeDesc returns true if condensation node b is a descendant of a in
+// the spanning forest of the first traversal.
+func (x *ReachIndex) treeDesc(a, b NI) bool {
+	return x.pre[a] <= x.pre[b] && x.rank[0][b] <= x.rank[0][a]
+}
+
+// Reachable returns true if there is a path from node a to node b.
+//
+// A node is always reachable from itself.
+func (x *ReachIndex) Reachable(a, b NI) bool {
+	ca, cb := x.cond[a], x.cond[b]
+	switch {
+	case ca == cb:
+		return true
+	case x.pos[ca] > x.pos[cb] || !x.contains(ca, cb):
+		return false
+	case x.treeDesc(ca, cb):
+		return true
+	}
+	// search, pruned by the index
+	x.query++
+	if x.query == 0 {
+		for i := range x.stamp {
+			x.stamp[i] = 0
+		}
+		x.query = 1
+	}
+	pb := x.pos[cb]
+	x.stamp[ca] = x.query
+	x.stack = append(x.stack[:0], ca)
+	for len(x.stack) > 0 {
+		n := x.stack[len(x.stack)-1]
+		x.stack = x.stack[:len(x.stack)-1]
+		for _, c := range x.cd[n] {
+			if c == cb || x.treeDesc(c, cb) {
+				return true
+			}
+			if x.stamp[c] != x.query && x.pos[c] < pb && x.contains(c, cb) {
+				x.stamp[c] = x.query
+				x.stack = append(x.stack, c)
+			}
+		}
+	}
+	return false
+}
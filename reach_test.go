@@ -0,0 +1,65 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleNewReachIndex() {
+	// 0-->1-->2   4
+	//     ^   |   |
+	//     |   v   v
+	//     +---3   5
+	g := graph.Directed{graph.AdjacencyList{
+		0: {1},
+		1: {2},
+		2: {3},
+		3: {1},
+		4: {5},
+		5: {},
+	}}
+	x := graph.NewReachIndex(g, 2, rand.New(rand.NewSource(1)))
+	fmt.Println(x.Reachable(0, 3), x.Reachable(3, 1), x.Reachable(3, 0))
+	fmt.Println(x.Reachable(4, 5), x.Reachable(0, 5))
+	// Output:
+	// true true false
+	// true false
+}
+
+func TestReachIndex(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(40)
+		g := graph.GnmDirected(n, r.Intn(2*n), r)
+		if i%2 == 0 {
+			// acyclic, arcs from lower to higher node numbers
+			a := make(graph.AdjacencyList, n)
+			for fr, to := range g.AdjacencyList {
+				for _, to := range to {
+					if graph.NI(fr) < to {
+						a[fr] = append(a[fr], to)
+					} else {
+						a[to] = append(a[to], graph.NI(fr))
+					}
+				}
+			}
+			g.AdjacencyList = a
+		}
+		tc := g.TransitiveClosure()
+		x := graph.NewReachIndex(g, 1+r.Intn(3), r)
+		for a := range tc {
+			for b := range tc {
+				want := a == b || tc[a].Bit(b) == 1
+				if got := x.Reachable(graph.NI(a), graph.NI(b)); got != want {
+					t.Fatalf("Reachable(%d, %d) = %t", a, b, got)
+				}
+			}
+		}
+	}
+}
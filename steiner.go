@@ -0,0 +1,357 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"sort"
+)
+
+// steiner.go has Steiner tree algorithms.
+//
+// A Steiner tree for a set of terminal nodes is a tree in the graph that
+// connects all terminals, possibly using other nodes as well.  A minimum
+// Steiner tree is one of minimum total edge weight.  Finding a minimum
+// Steiner tree is NP-hard.  SteinerDreyfusWagner finds one in time
+// exponential in the number of terminals.  SteinerKMB and SteinerMehlhorn
+// find trees within a factor of 2 of minimum in polynomial time.
+//
+// The functions share these conventions:
+//
+// Edge weights are given by WeightFunc w and must be non-negative.
+// Terminals must be distinct.
+//
+// The tree is returned as a subgraph of g.  Terminals are the first nodes
+// of the subgraph, in the order given, followed by any other nodes used.
+// Also returned is the total weight of the tree.  If terminals is empty,
+// an empty subgraph is returned with weight 0.  If the terminals are not
+// all connected in g, the returned subgraph is nil and weight is +Inf.
+
+// SteinerDreyfusWagner computes a minimum Steiner tree.
+//
+// See the steiner.go file comment for conventions of arguments and
+// return values.
+//
+// The algorithm is that of Dreyfus and Wagner, with time complexity
+// O(3^k n + 2^k m log n) and space O(2^k n) for k terminals, n nodes, and
+// m edges.  It is practical only for small numbers of terminals.
+func (g *LabeledUndirected) SteinerDreyfusWagner(terminals []NI, w WeightFunc) (s *LabeledUndirectedSubgraph, dist float64) {
+	// Ref: "The Steiner problem in graphs", S. E. Dreyfus and R. A. Wagner,
+	// Networks 1 (1971).
+	if len(terminals) < 2 {
+		return g.steinerTree(terminals, nil, w)
+	}
+	// subsets of terminals other than the last.  the last is the root of
+	// the final tree.
+	k := len(terminals) - 1
+	n := g.Order()
+	type dwTree struct {
+		dist  []float64
+		from  []NI // last arc of path to node, or -1
+		label []LI
+		split []int // subset for trees joined at node
+	}
+	dp := make([]dwTree, 1<<uint(k))
+	for set := 1; set < len(dp); set++ {
+		t := dwTree{
+			dist:  make([]float64, n),
+			from:  make([]NI, n),
+			label: make([]LI, n),
+			split: make([]int, n),
+		}
+		for v := range t.dist {
+			t.dist[v] = math.Inf(1)
+			t.from[v] = -1
+		}
+		if set&(set-1) == 0 {
+			// singleton
+			for i := 0; i < k; i++ {
+				if set == 1<<uint(i) {
+					t.dist[terminals[i]] = 0
+				}
+			}
+		} else {
+			// join trees for complementary subsets.  consider each
+			// unordered pair once by requiring sub to have the lowest
+			// bit of set.
+			low := set & -set
+			for sub := (set - 1) & set; sub > 0; sub = (sub - 1) & set {
+				if sub&low == 0 {
+					continue
+				}
+				d1, d2 := dp[sub].dist, dp[set^sub].dist
+				for v := range t.dist {
+					if d := d1[v] + d2[v]; d < t.dist[v] {
+						t.dist[v] = d
+						t.split[v] = sub
+					}
+				}
+			}
+		}
+		g.steinerRelax(t.dist, t.from, t.label, nil, w)
+		dp[set] = t
+	}
+	full := len(dp) - 1
+	root := terminals[k]
+	if math.IsInf(dp[full].dist[root], 1) {
+		return nil, math.Inf(1)
+	}
+	// reconstruct edges
+	var edges []LabeledEdge
+	var rec func(set int, v NI)
+	rec = func(set int, v NI) {
+		t := &dp[set]
+		switch {
+		case t.from[v] >= 0:
+			edges = append(edges, LabeledEdge{Edge{t.from[v], v}, t.label[v]})
+			rec(set, t.from[v])
+		case t.split[v] > 0:
+			rec(t.split[v], v)
+			rec(set^t.split[v], v)
+		}
+	}
+	rec(full, root)
+	return g.steinerTree(terminals, edges, w)
+}
+
+// SteinerKMB computes an approximate minimum Steiner tree.
+//
+// See the steiner.go file comment for conventions of arguments and
+// return values.
+//
+// The algorithm is that of Kou, Markowsky, and Berman.  It constructs the
+// metric closure of the terminals, the complete graph of shortest path
+// distances between terminals, then expands a minimum spanning tree of the
+// metric closure into the corresponding shortest paths in g.  The result
+// has weight less than 2 times the minimum.  Time complexity is
+// O(k m log n) for k terminals, n nodes, and m edges.
+//
+// See also SteinerMehlhorn, which gives a tree of the same approximation
+// ratio in less time.
+func (g *LabeledUndirected) SteinerKMB(terminals []NI, w WeightFunc) (s *LabeledUndirectedSubgraph, dist float64) {
+	// Ref: "A fast algorithm for Steiner trees", L. Kou, G. Markowsky, and
+	// L. Berman, Acta Informatica 15 (1981).
+	if len(terminals) < 2 {
+		return g.steinerTree(terminals, nil, w)
+	}
+	type sp struct {
+		f      FromList
+		labels []LI
+		dist   []float64
+	}
+	paths := make([]sp, len(terminals))
+	// metric closure as a weighted edge list on terminal indexes
+	closure := WeightedEdgeList{Order: len(terminals)}
+	var cw []float64
+	closure.WeightFunc = func(l LI) float64 { return cw[l] }
+	for i, t := range terminals {
+		f, labels, dist, _ := g.LabeledAdjacencyList.Dijkstra(t, -1, w)
+		paths[i] = sp{f, labels, dist}
+		for j := 0; j < i; j++ {
+			if f.Paths[terminals[j]].Len == 0 {
+				return nil, math.Inf(1)
+			}
+			closure.Edges = append(closure.Edges, LabeledEdge{Edge{NI(i), NI(j)}, LI(len(cw))})
+			cw = append(cw, dist[terminals[j]])
+		}
+	}
+	mst, _ := closure.Kruskal()
+	var edges []LabeledEdge
+	mst.Edges(func(e LabeledEdge) {
+		p := paths[e.N1]
+		for n := terminals[e.N2]; n != terminals[e.N1]; {
+			fr := p.f.Paths[n].From
+			edges = append(edges, LabeledEdge{Edge{fr, n}, p.labels[n]})
+			n = fr
+		}
+	})
+	return g.steinerTree(terminals, edges, w)
+}
+
+// SteinerMehlhorn computes an approximate minimum Steiner tree.
+//
+// See the steiner.go file comment for conventions of arguments and
+// return values.
+//
+// The algorithm is Mehlhorn's improvement of the method of Kou, Markowsky,
+// and Berman.  Rather than the full metric closure of the terminals, it
+// uses a single shortest path search from all terminals at once to
+// partition nodes by nearest terminal.  Edges between partitions then give
+// a subgraph of the metric closure sufficient for the approximation.  The
+// result has weight less than 2 times the minimum.  Time complexity is
+// O(m log n) for n nodes and m edges.
+func (g *LabeledUndirected) SteinerMehlhorn(terminals []NI, w WeightFunc) (s *LabeledUndirectedSubgraph, dist float64) {
+	// Ref: "A faster approximation algorithm for the Steiner problem in
+	// graphs", Kurt Mehlhorn, Information Processing Letters 27 (1988).
+	if len(terminals) < 2 {
+		return g.steinerTree(terminals, nil, w)
+	}
+	a := g.LabeledAdjacencyList
+	d := make([]float64, len(a))
+	from := make([]NI, len(a))
+	labels := make([]LI, len(a))
+	src := make([]NI, len(a))
+	for n := range d {
+		d[n] = math.Inf(1)
+		from[n] = -1
+		src[n] = -1
+	}
+	for i, t := range terminals {
+		d[t] = 0
+		src[t] = NI(i)
+	}
+	g.steinerRelax(d, from, labels, src, w)
+	// edges between regions of nearest terminals give the reduced
+	// metric closure.  closure edge labels index the bridging edges.
+	type bridge struct {
+		e  LabeledEdge
+		wt float64
+	}
+	var bridges []bridge
+	closure := WeightedEdgeList{
+		Order:      len(terminals),
+		WeightFunc: func(l LI) float64 { return bridges[l].wt },
+	}
+	for fr, to := range a {
+		for _, to := range to {
+			s1, s2 := src[fr], src[to.To]
+			if s1 < 0 || s2 < 0 || s1 >= s2 {
+				continue
+			}
+			closure.Edges = append(closure.Edges, LabeledEdge{Edge{s1, s2}, LI(len(bridges))})
+			bridges = append(bridges, bridge{
+				LabeledEdge{Edge{NI(fr), to.To}, to.Label},
+				d[fr] + w(to.Label) + d[to.To],
+			})
+		}
+	}
+	mst, _ := closure.Kruskal()
+	if _, nc := mst.ConnectedComponentInts(); nc > 1 {
+		return nil, math.Inf(1)
+	}
+	var edges []LabeledEdge
+	mst.Edges(func(e LabeledEdge) {
+		b := bridges[e.LI].e
+		edges = append(edges, b)
+		for _, n := range []NI{b.N1, b.N2} {
+			for ; from[n] >= 0; n = from[n] {
+				edges = append(edges, LabeledEdge{Edge{from[n], n}, labels[n]})
+			}
+		}
+	})
+	return g.steinerTree(terminals, edges, w)
+}
+
+// steinerRelax computes shortest paths from multiple sources.
+//
+// On entry, dist holds initial distances, +Inf for nodes not yet reached.
+// On return, dist holds shortest distances, from and label hold the last
+// arc of each shortest path, or -1 in from where no arc improved on the
+// initial distance.  If src is non-nil, it is propagated along paths.
+func (g LabeledUndirected) steinerRelax(dist []float64, from []NI, label []LI, src []NI, w WeightFunc) {
+	a := g.LabeledAdjacencyList
+	r := make([]tentResult, len(a))
+	var t tent
+	for n := range r {
+		r[n].nx = NI(n)
+		r[n].dist = dist[n]
+		r[n].fx = -1
+		if !math.IsInf(dist[n], 1) {
+			heap.Push(&t, &r[n])
+		}
+	}
+	for len(t) > 0 {
+		cr := heap.Pop(&t).(*tentResult)
+		cr.done = true
+		n := cr.nx
+		for _, nb := range a[n] {
+			hr := &r[nb.To]
+			if hr.done {
+				continue
+			}
+			d := cr.dist + w(nb.Label)
+			if d >= hr.dist {
+				continue
+			}
+			hr.dist = d
+			dist[nb.To] = d
+			from[nb.To] = n
+			label[nb.To] = nb.Label
+			if src != nil {
+				src[nb.To] = src[n]
+			}
+			if hr.fx >= 0 {
+				heap.Fix(&t, hr.fx)
+			} else {
+				heap.Push(&t, hr)
+			}
+		}
+	}
+}
+
+// steinerTree constructs the result subgraph for the Steiner functions.
+//
+// Argument edges is a list of edges of g, possibly with duplicates,
+// connecting the terminals.  A minimum spanning tree of these edges is
+// computed and then non-terminal leaves are pruned.
+func (g *LabeledUndirected) steinerTree(terminals []NI, edges []LabeledEdge, w WeightFunc) (s *LabeledUndirectedSubgraph, dist float64) {
+	s = g.InduceList(nil)
+	for _, t := range terminals {
+		s.AddNode(t)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		return w(edges[i].LI) < w(edges[j].LI)
+	})
+	ds := NewUnionFind(g.Order())
+	tree := edges[:0]
+	adj := make([][]int, g.Order()) // indexes into tree
+	for _, e := range edges {
+		if ds.Union(e.N1, e.N2) {
+			adj[e.N1] = append(adj[e.N1], len(tree))
+			adj[e.N2] = append(adj[e.N2], len(tree))
+			tree = append(tree, e)
+		}
+	}
+	// prune non-terminal leaves.  removed edges are marked by setting N1
+	// to -1.
+	deg := make([]int, len(adj))
+	isTerm := make([]bool, len(adj))
+	for _, t := range terminals {
+		isTerm[t] = true
+	}
+	var leaves []NI
+	for n, x := range adj {
+		deg[n] = len(x)
+		if deg[n] == 1 && !isTerm[n] {
+			leaves = append(leaves, NI(n))
+		}
+	}
+	for len(leaves) > 0 {
+		n := leaves[len(leaves)-1]
+		leaves = leaves[:len(leaves)-1]
+		for _, x := range adj[n] {
+			e := &tree[x]
+			if e.N1 < 0 {
+				continue
+			}
+			m := e.N1
+			if m == n {
+				m = e.N2
+			}
+			e.N1 = -1
+			deg[n]--
+			if deg[m]--; deg[m] == 1 && !isTerm[m] {
+				leaves = append(leaves, m)
+			}
+		}
+	}
+	for _, e := range tree {
+		if e.N1 >= 0 {
+			s.AddEdge(e.Edge, e.LI)
+			dist += w(e.LI)
+		}
+	}
+	return
+}
@@ -0,0 +1,189 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleLabeledUndirected_SteinerDreyfusWagner() {
+	//     (1)     (1)
+	//  0-------4-------1
+	//   \      |      /
+	// (3)\  (1)|     /(3)
+	//     \    |    /
+	//      ----2----
+	//          |(4)
+	//          3
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 4}, 1)
+	g.AddEdge(graph.Edge{4, 1}, 1)
+	g.AddEdge(graph.Edge{4, 2}, 1)
+	g.AddEdge(graph.Edge{0, 2}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 3)
+	g.AddEdge(graph.Edge{2, 3}, 4)
+	w := func(l graph.LI) float64 { return float64(l) }
+	s, dist := g.SteinerDreyfusWagner([]graph.NI{0, 1, 2}, w)
+	fmt.Println("weight:", dist)
+	fmt.Println("super NIs:", s.SuperNI)
+	s.Edges(func(e graph.LabeledEdge) {
+		fmt.Println(s.SuperNI[e.N1], s.SuperNI[e.N2], e.LI)
+	})
+	// Output:
+	// weight: 3
+	// super NIs: [0 1 2 4]
+	// 4 2 1
+	// 4 0 1
+	// 4 1 1
+}
+
+func ExampleLabeledUndirected_SteinerKMB() {
+	//     (1)     (1)
+	//  0-------4-------1
+	//   \      |      /
+	// (3)\  (1)|     /(3)
+	//     \    |    /
+	//      ----2----
+	//          |(4)
+	//          3
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 4}, 1)
+	g.AddEdge(graph.Edge{4, 1}, 1)
+	g.AddEdge(graph.Edge{4, 2}, 1)
+	g.AddEdge(graph.Edge{0, 2}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 3)
+	g.AddEdge(graph.Edge{2, 3}, 4)
+	w := func(l graph.LI) float64 { return float64(l) }
+	s, dist := g.SteinerKMB([]graph.NI{0, 1, 2}, w)
+	fmt.Println("weight:", dist)
+	fmt.Println("super NIs:", s.SuperNI)
+	s.Edges(func(e graph.LabeledEdge) {
+		fmt.Println(s.SuperNI[e.N1], s.SuperNI[e.N2], e.LI)
+	})
+	// Output:
+	// weight: 3
+	// super NIs: [0 1 2 4]
+	// 4 0 1
+	// 4 1 1
+	// 4 2 1
+}
+
+func ExampleLabeledUndirected_SteinerMehlhorn() {
+	//     (1)     (1)
+	//  0-------4-------1
+	//   \      |      /
+	// (3)\  (1)|     /(3)
+	//     \    |    /
+	//      ----2----
+	//          |(4)
+	//          3
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 4}, 1)
+	g.AddEdge(graph.Edge{4, 1}, 1)
+	g.AddEdge(graph.Edge{4, 2}, 1)
+	g.AddEdge(graph.Edge{0, 2}, 3)
+	g.AddEdge(graph.Edge{1, 2}, 3)
+	g.AddEdge(graph.Edge{2, 3}, 4)
+	w := func(l graph.LI) float64 { return float64(l) }
+	s, dist := g.SteinerMehlhorn([]graph.NI{0, 1, 2}, w)
+	fmt.Println("weight:", dist)
+	fmt.Println("super NIs:", s.SuperNI)
+	s.Edges(func(e graph.LabeledEdge) {
+		fmt.Println(s.SuperNI[e.N1], s.SuperNI[e.N2], e.LI)
+	})
+	// Output:
+	// weight: 3
+	// super NIs: [0 1 2 4]
+	// 4 1 1
+	// 4 0 1
+	// 4 2 1
+}
+
+func TestSteiner(t *testing.T) {
+	r := rand.New(rand.NewSource(5))
+	for i := 0; i < 100; i++ {
+		n := 2 + r.Intn(8)
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		wt := []float64{}
+		for j := r.Intn(3 * n); j > 0; j-- {
+			g.AddEdge(graph.Edge{graph.NI(r.Intn(n)), graph.NI(r.Intn(n))},
+				graph.LI(len(wt)))
+			wt = append(wt, float64(r.Intn(10)))
+		}
+		w := func(l graph.LI) float64 { return wt[l] }
+		terms := r.Perm(n)[:1+r.Intn(n)]
+		tn := make([]graph.NI, len(terms))
+		for i, x := range terms {
+			tn[i] = graph.NI(x)
+		}
+		// brute force: minimum spanning tree over each node set
+		// containing the terminals.
+		want := math.Inf(1)
+		for set := 0; set < 1<<uint(n); set++ {
+			ok := true
+			for _, x := range terms {
+				ok = ok && set>>uint(x)&1 == 1
+			}
+			if !ok {
+				continue
+			}
+			l := graph.WeightedEdgeList{Order: n, WeightFunc: w}
+			g.Edges(func(e graph.LabeledEdge) {
+				if set>>uint(e.N1)&1 == 1 && set>>uint(e.N2)&1 == 1 {
+					l.Edges = append(l.Edges, e)
+				}
+			})
+			f, d := l.Kruskal()
+			ci, _ := f.ConnectedComponentInts()
+			for _, x := range terms {
+				ok = ok && ci[x] == ci[terms[0]]
+			}
+			if ok && d < want {
+				want = d
+			}
+		}
+		check := func(name string, s *graph.LabeledUndirectedSubgraph, d float64) {
+			if math.IsInf(want, 1) {
+				if s != nil || !math.IsInf(d, 1) {
+					t.Fatalf("%s found tree for disconnected terminals", name)
+				}
+				return
+			}
+			if d < want || d > 2*want {
+				t.Fatalf("%s weight %g, minimum %g", name, d, want)
+			}
+			if !s.IsConnected() {
+				t.Fatalf("%s tree not connected", name)
+			}
+			if _, _, _, simple := s.FromList(); !simple {
+				t.Fatalf("%s result not a tree", name)
+			}
+			for i, x := range tn {
+				if s.SuperNI[i] != x {
+					t.Fatalf("%s terminals not first in subgraph", name)
+				}
+			}
+			sum := 0.
+			s.Edges(func(e graph.LabeledEdge) { sum += w(e.LI) })
+			if sum != d {
+				t.Fatalf("%s returned weight %g, tree weight %g", name, d, sum)
+			}
+		}
+		s, d := g.SteinerDreyfusWagner(tn, w)
+		check("DreyfusWagner", s, d)
+		if d != want && !math.IsInf(want, 1) {
+			t.Fatalf("DreyfusWagner weight %g, want %g", d, want)
+		}
+		s, d = g.SteinerKMB(tn, w)
+		check("KMB", s, d)
+		s, d = g.SteinerMehlhorn(tn, w)
+		check("Mehlhorn", s, d)
+	}
+}
@@ -0,0 +1,391 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+)
+
+// tree.go has algorithms specific to trees.
+//
+// Rooted trees and forests are represented as FromLists.  Free, or unrooted,
+// trees are represented as Undirected graphs.  Methods on Undirected here
+// require the graph to be a tree, connected with no cycles, loops, or
+// parallel edges.  See Undirected.IsTree.
+
+// SubtreeSizes returns the number of nodes in the subtree at each node of
+// a forest.
+//
+// The FromList must be acyclic.  Len members are not used.
+func (f FromList) SubtreeSizes() []int {
+	p := f.Paths
+	size := make([]int, len(p))
+	nc := make([]int, len(p)) // children not yet counted
+	for _, e := range p {
+		if e.From >= 0 {
+			nc[e.From]++
+		}
+	}
+	var q []NI
+	for n, c := range nc {
+		if c == 0 {
+			q = append(q, NI(n))
+		}
+	}
+	for len(q) > 0 {
+		n := q[len(q)-1]
+		q = q[:len(q)-1]
+		size[n]++
+		if fr := p[n].From; fr >= 0 {
+			size[fr] += size[n]
+			if nc[fr]--; nc[fr] == 0 {
+				q = append(q, fr)
+			}
+		}
+	}
+	return size
+}
+
+// HeavyLight is a heavy-light decomposition of a forest.
+//
+// Each non-leaf node has a heavy child, a child with the largest subtree.
+// Arcs to heavy children form heavy paths which partition the nodes.  A path
+// between any two nodes of a tree intersects O(log n) heavy paths.
+//
+// Nodes are listed in Order such that each heavy path is contiguous,
+// starting from its head, the node closest to the root.  Each subtree is
+// also contiguous, starting from its root.  The subtree at node n occupies
+// positions Pos[n] through Pos[n]+size-1, where size is the subtree size.
+// This allows path and subtree queries to be answered with a segment tree or
+// similar structure over Order.
+type HeavyLight struct {
+	Heavy []NI  // heavy child of each node, -1 for leaves
+	Head  []NI  // head of the heavy path containing each node
+	Pos   []int // position of each node in Order
+	Order []NI  // nodes with heavy paths and subtrees contiguous
+	Depth []int // number of arcs from the root to each node
+	from  []NI
+}
+
+// HeavyLight computes a heavy-light decomposition of a forest.
+//
+// The FromList must be acyclic.  Len members are not used.  Ties for heavy
+// children are broken by the lesser node number.
+func (f FromList) HeavyLight() *HeavyLight {
+	p := f.Paths
+	size := f.SubtreeSizes()
+	h := &HeavyLight{
+		Heavy: make([]NI, len(p)),
+		Head:  make([]NI, len(p)),
+		Pos:   make([]int, len(p)),
+		Order: make([]NI, 0, len(p)),
+		Depth: make([]int, len(p)),
+		from:  make([]NI, len(p)),
+	}
+	for n := range p {
+		h.Heavy[n] = -1
+	}
+	// children lists, in node order
+	ch := make([][]NI, len(p))
+	for n, e := range p {
+		h.from[n] = e.From
+		if fr := e.From; fr >= 0 {
+			ch[fr] = append(ch[fr], NI(n))
+			if hv := h.Heavy[fr]; hv < 0 || size[n] > size[hv] {
+				h.Heavy[fr] = NI(n)
+			}
+		}
+	}
+	// depth first, heavy child first
+	var stack []NI
+	for r, e := range p {
+		if e.From >= 0 {
+			continue
+		}
+		h.Head[r] = NI(r)
+		stack = append(stack[:0], NI(r))
+		for len(stack) > 0 {
+			n := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			h.Pos[n] = len(h.Order)
+			h.Order = append(h.Order, n)
+			hv := h.Heavy[n]
+			for i := len(ch[n]) - 1; i >= 0; i-- {
+				c := ch[n][i]
+				h.Depth[c] = h.Depth[n] + 1
+				if c == hv {
+					continue
+				}
+				h.Head[c] = c
+				stack = append(stack, c)
+			}
+			if hv >= 0 {
+				h.Head[hv] = h.Head[n]
+				stack = append(stack, hv) // pushed last, popped first
+			}
+		}
+	}
+	return h
+}
+
+// Segments returns the segments of Order covering the tree path between
+// nodes a and b.
+//
+// Each segment is a pair of positions, inclusive, in Order.  Segments are
+// not in path order.  Also returned is the lowest common ancestor of a and b.
+// If a and b are in different trees, segs is nil and lca is -1.
+func (h *HeavyLight) Segments(a, b NI) (segs [][2]int, lca NI) {
+	for h.Head[a] != h.Head[b] {
+		if h.Depth[h.Head[a]] < h.Depth[h.Head[b]] {
+			a, b = b, a
+		}
+		segs = append(segs, [2]int{h.Pos[h.Head[a]], h.Pos[a]})
+		if a = h.from[h.Head[a]]; a < 0 {
+			return nil, -1
+		}
+	}
+	if h.Depth[a] > h.Depth[b] {
+		a, b = b, a
+	}
+	return append(segs, [2]int{h.Pos[a], h.Pos[b]}), a
+}
+
+// treeBFS returns a breadth first ordering of the tree containing root,
+// and the parent of each node in the ordering.
+func treeBFS(a AdjacencyList, root NI) (order, from []NI) {
+	from = make([]NI, len(a))
+	from[root] = -1
+	order = append(make([]NI, 0, len(a)), root)
+	for i := 0; i < len(order); i++ {
+		n := order[i]
+		for _, to := range a[n] {
+			if to != from[n] {
+				from[to] = n
+				order = append(order, to)
+			}
+		}
+	}
+	return
+}
+
+// isFreeTree returns true if g is a tree.
+func (g Undirected) isFreeTree() bool {
+	if len(g.AdjacencyList) == 0 {
+		return false
+	}
+	t, all := g.IsTree(0)
+	return t && all
+}
+
+// TreeCentroids returns the centroids of a tree.
+//
+// A centroid is a node whose removal leaves components of at most n/2 nodes.
+// A tree has one centroid or two adjacent centroids.  Centroids are returned
+// in ascending order.
+//
+// If g is not a tree, the result is nil.
+func (g Undirected) TreeCentroids() []NI {
+	if !g.isFreeTree() {
+		return nil
+	}
+	return treeCentroids(g.AdjacencyList)
+}
+
+func treeCentroids(a AdjacencyList) (c []NI) {
+	order, from := treeBFS(a, 0)
+	size := make([]int, len(a))
+	big := make([]int, len(a)) // largest component after removal
+	for i := len(order) - 1; i >= 0; i-- {
+		n := order[i]
+		size[n]++
+		if fr := from[n]; fr >= 0 {
+			size[fr] += size[n]
+			if size[n] > big[fr] {
+				big[fr] = size[n]
+			}
+		}
+	}
+	for n := range a {
+		if r := len(a) - size[n]; r > big[n] {
+			big[n] = r
+		}
+		if 2*big[n] <= len(a) {
+			c = append(c, NI(n))
+		}
+	}
+	return
+}
+
+// TreeDiameter returns a longest path in a tree.
+//
+// The diameter of the tree, the number of edges in the path, is
+// len(path)-1.  The path is found with two breadth first searches.
+//
+// If g is not a tree, the result is nil.
+func (g Undirected) TreeDiameter() (path []NI) {
+	if !g.isFreeTree() {
+		return nil
+	}
+	a := g.AdjacencyList
+	order, _ := treeBFS(a, 0)
+	order, from := treeBFS(a, order[len(order)-1])
+	for n := order[len(order)-1]; n >= 0; n = from[n] {
+		path = append(path, n)
+	}
+	return path
+}
+
+// TreeIsomorphic returns true if trees g and h are isomorphic.
+//
+// The test uses AHU canonical names of the trees rooted at their centroids.
+//
+// Ref: "The Design and Analysis of Computer Algorithms", Alfred V. Aho,
+// John E. Hopcroft, and Jeffrey D. Ullman, Addison-Wesley (1974).
+//
+// If either g or h is not a tree, the result is false.
+func (g Undirected) TreeIsomorphic(h Undirected) bool {
+	if g.Order() != h.Order() || !g.isFreeTree() || !h.isFreeTree() {
+		return false
+	}
+	cg := treeCentroids(g.AdjacencyList)
+	ch := treeCentroids(h.AdjacencyList)
+	if len(cg) != len(ch) {
+		return false
+	}
+	names := map[string]int{}
+	ng := ahuName(g.AdjacencyList, cg[0], names)
+	for _, c := range ch {
+		if ahuName(h.AdjacencyList, c, names) == ng {
+			return true
+		}
+	}
+	return false
+}
+
+// RootedTreeIsomorphic returns true if tree g rooted at gRoot is isomorphic
+// to tree h rooted at hRoot, with the isomorphism mapping gRoot to hRoot.
+//
+// The test uses AHU canonical names.  See TreeIsomorphic.
+//
+// If either g or h is not a tree, the result is false.
+func (g Undirected) RootedTreeIsomorphic(gRoot NI, h Undirected, hRoot NI) bool {
+	if g.Order() != h.Order() || !g.isFreeTree() || !h.isFreeTree() {
+		return false
+	}
+	names := map[string]int{}
+	return ahuName(g.AdjacencyList, gRoot, names) ==
+		ahuName(h.AdjacencyList, hRoot, names)
+}
+
+// ahuName returns the canonical name of the tree rooted at root.
+//
+// Names are integers assigned to the sorted lists of child names as they
+// are first seen.  Isomorphic rooted trees get the same name when names
+// is shared.
+func ahuName(a AdjacencyList, root NI, names map[string]int) int {
+	order, from := treeBFS(a, root)
+	name := make([]int, len(a))
+	var cn []int
+	var key []byte
+	for i := len(order) - 1; i >= 0; i-- {
+		n := order[i]
+		cn = cn[:0]
+		for _, to := range a[n] {
+			if to != from[n] {
+				cn = append(cn, name[to])
+			}
+		}
+		sort.Ints(cn)
+		key = key[:0]
+		for _, c := range cn {
+			key = strconv.AppendInt(key, int64(c), 36)
+			key = append(key, ',')
+		}
+		x, ok := names[string(key)]
+		if !ok {
+			x = len(names)
+			names[string(key)] = x
+		}
+		name[n] = x
+	}
+	return name[root]
+}
+
+// PruferCode returns the Prüfer sequence of a tree.
+//
+// The sequence has length n-2 for a tree of n >= 2 nodes.  It is computed
+// in linear time.  See also PruferTree.
+//
+// If g is not a tree of at least two nodes, an error is returned.
+func (g Undirected) PruferCode() ([]NI, error) {
+	a := g.AdjacencyList
+	if len(a) < 2 || !g.isFreeTree() {
+		return nil, errors.New("not a tree of at least two nodes")
+	}
+	deg := make([]int, len(a))
+	for n, to := range a {
+		deg[n] = len(to)
+	}
+	_, from := treeBFS(a, NI(len(a)-1)) // parents toward the last node
+	code := make([]NI, 0, len(a)-2)
+	ptr := 0
+	for deg[ptr] != 1 {
+		ptr++
+	}
+	leaf := ptr
+	for len(code) < len(a)-2 {
+		p := from[leaf]
+		code = append(code, p)
+		if deg[p]--; deg[p] == 1 && int(p) < ptr {
+			leaf = int(p)
+			continue
+		}
+		for ptr++; deg[ptr] != 1; ptr++ {
+		}
+		leaf = ptr
+	}
+	return code, nil
+}
+
+// PruferTree constructs the tree with the given Prüfer sequence.
+//
+// The tree has len(code)+2 nodes.  Values in code must be valid node
+// numbers, in the range 0 to len(code)+1.  If not, an error is returned.
+//
+// The tree is constructed in linear time.  See also Undirected.PruferCode.
+func PruferTree(code []NI) (Undirected, error) {
+	n := len(code) + 2
+	deg := make([]int, n)
+	for i := range deg {
+		deg[i] = 1
+	}
+	for _, c := range code {
+		if c < 0 || int(c) >= n {
+			return Undirected{}, fmt.Errorf("PruferTree: value %d out of range", c)
+		}
+		deg[c]++
+	}
+	var g Undirected
+	g.AdjacencyList = make(AdjacencyList, n)
+	ptr := 0
+	for deg[ptr] != 1 {
+		ptr++
+	}
+	leaf := ptr
+	for _, c := range code {
+		g.AddEdge(NI(leaf), c)
+		if deg[c]--; deg[c] == 1 && int(c) < ptr {
+			leaf = int(c)
+			continue
+		}
+		for ptr++; deg[ptr] != 1; ptr++ {
+		}
+		leaf = ptr
+	}
+	g.AddEdge(NI(leaf), NI(n-1))
+	return g, nil
+}
@@ -0,0 +1,404 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleFromList_SubtreeSizes() {
+	//   4   5
+	//  /   /
+	// 6   1
+	//    / \
+	//   0   2
+	//  /
+	// 3
+	f := graph.FromList{Paths: []graph.PathEnd{
+		4: {From: -1},
+		6: {From: 4},
+		5: {From: -1},
+		1: {From: 5},
+		0: {From: 1},
+		2: {From: 1},
+		3: {From: 0},
+	}}
+	fmt.Println(f.SubtreeSizes())
+	// Output:
+	// [2 4 1 1 2 5 1]
+}
+
+func ExampleFromList_HeavyLight() {
+	//   4   5
+	//  /   /
+	// 6   1
+	//    / \
+	//   0   2
+	//  /
+	// 3
+	f := graph.FromList{Paths: []graph.PathEnd{
+		4: {From: -1},
+		6: {From: 4},
+		5: {From: -1},
+		1: {From: 5},
+		0: {From: 1},
+		2: {From: 1},
+		3: {From: 0},
+	}}
+	h := f.HeavyLight()
+	fmt.Println("Heavy:", h.Heavy)
+	fmt.Println("Head: ", h.Head)
+	fmt.Println("Order:", h.Order)
+	fmt.Println(h.Segments(2, 3))
+	fmt.Println(h.Segments(6, 3))
+	// Output:
+	// Heavy: [3 0 -1 -1 6 1 -1]
+	// Head:  [5 5 2 5 4 5 4]
+	// Order: [4 6 5 1 0 3 2]
+	// [[6 6] [3 5]] 1
+	// [] -1
+}
+
+func ExampleUndirected_TreeCentroids() {
+	// 0--1--2--3
+	//    |
+	//    4--5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(4, 5)
+	fmt.Println(g.TreeCentroids())
+	// extend to 0--1--2--3
+	//              |
+	//              4--5--6--7
+	g.AddEdge(5, 6)
+	g.AddEdge(6, 7)
+	fmt.Println(g.TreeCentroids())
+	// Output:
+	// [1]
+	// [1 4]
+}
+
+func ExampleUndirected_TreeDiameter() {
+	// 0--1--2--3
+	//    |
+	//    4--5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(4, 5)
+	p := g.TreeDiameter()
+	fmt.Println(p, len(p)-1)
+	// Output:
+	// [3 2 1 4 5] 4
+}
+
+func ExampleUndirected_TreeIsomorphic() {
+	// 0--1--2--3     0--1--2
+	//    |              |
+	//    4              3--4
+	var g, h graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(1, 4)
+	h.AddEdge(0, 1)
+	h.AddEdge(1, 2)
+	h.AddEdge(1, 3)
+	h.AddEdge(3, 4)
+	fmt.Println(g.TreeIsomorphic(h))
+	fmt.Println(g.RootedTreeIsomorphic(3, h, 4))
+	fmt.Println(g.RootedTreeIsomorphic(3, h, 2))
+	// Output:
+	// true
+	// true
+	// false
+}
+
+func ExampleUndirected_PruferCode() {
+	// 0--1--2--3
+	//    |
+	//    4--5
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(1, 4)
+	g.AddEdge(4, 5)
+	fmt.Println(g.PruferCode())
+	// Output:
+	// [1 2 1 4] <nil>
+}
+
+func ExamplePruferTree() {
+	g, err := graph.PruferTree([]graph.NI{1, 2, 1, 4})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	for n, to := range g.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// 0 [1]
+	// 1 [0 2 4]
+	// 2 [3 1]
+	// 3 [2]
+	// 4 [1 5]
+	// 5 [4]
+}
+
+func TestPrufer(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	// all 4^2 trees on 4 nodes are generated with similar frequency
+	count := map[string]int{}
+	for i := 0; i < 1600; i++ {
+		g := graph.RandomTree(4, r)
+		c, err := g.PruferCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		count[fmt.Sprint(c)]++
+	}
+	if len(count) != 16 {
+		t.Fatal("trees generated:", len(count))
+	}
+	for c, k := range count {
+		if k < 50 || k > 150 {
+			t.Fatalf("tree %s generated %d times", c, k)
+		}
+	}
+	for i := 0; i < 100; i++ {
+		n := 2 + r.Intn(30)
+		g := graph.RandomTree(n, r)
+		if g.Order() != n || g.Size() != n-1 {
+			t.Fatal("not a tree")
+		}
+		c, err := g.PruferCode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		h, err := graph.PruferTree(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(sortedEdges(g), sortedEdges(h)) {
+			t.Fatal("decode differs")
+		}
+	}
+	if _, err := graph.PruferTree([]graph.NI{4}); err == nil {
+		t.Fatal("no error for value out of range")
+	}
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 0)
+	if _, err := g.PruferCode(); err == nil {
+		t.Fatal("no error for cycle")
+	}
+}
+
+// sortedEdges returns edges of g as a set.
+func sortedEdges(g graph.Undirected) map[graph.Edge]bool {
+	m := map[graph.Edge]bool{}
+	g.Edges(func(e graph.Edge) {
+		if e.N1 > e.N2 {
+			e.N1, e.N2 = e.N2, e.N1
+		}
+		m[e] = true
+	})
+	return m
+}
+
+// treeDist returns all pairs distances in a tree by breadth first search.
+func treeDist(g graph.Undirected) [][]int {
+	a := g.AdjacencyList
+	d := make([][]int, len(a))
+	for s := range a {
+		d[s] = make([]int, len(a))
+		for i := range d[s] {
+			d[s][i] = -1
+		}
+		d[s][s] = 0
+		q := []graph.NI{graph.NI(s)}
+		for len(q) > 0 {
+			n := q[0]
+			q = q[1:]
+			for _, to := range a[n] {
+				if d[s][to] < 0 {
+					d[s][to] = d[s][n] + 1
+					q = append(q, to)
+				}
+			}
+		}
+	}
+	return d
+}
+
+func TestTreeCentroidsDiameter(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(20)
+		g := graph.RandomTree(n, r)
+		d := treeDist(g)
+		// diameter
+		want := 0
+		for _, di := range d {
+			for _, dij := range di {
+				if dij > want {
+					want = dij
+				}
+			}
+		}
+		p := g.TreeDiameter()
+		if len(p)-1 != want || d[p[0]][p[len(p)-1]] != want {
+			t.Fatalf("diameter path %v, want length %d", p, want)
+		}
+		// centroids by removing each node
+		var wantC []graph.NI
+		for v := 0; v < n; v++ {
+			big := 0
+			for _, to := range g.AdjacencyList[v] {
+				// component of to after removing v: nodes closer to to
+				c := 0
+				for u := 0; u < n; u++ {
+					if d[to][u] < d[v][u] {
+						c++
+					}
+				}
+				if c > big {
+					big = c
+				}
+			}
+			if 2*big <= n {
+				wantC = append(wantC, graph.NI(v))
+			}
+		}
+		if c := g.TreeCentroids(); !reflect.DeepEqual(c, wantC) {
+			t.Fatalf("centroids %v, want %v", c, wantC)
+		}
+	}
+}
+
+func TestTreeIsomorphic(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 300; i++ {
+		n := 1 + r.Intn(7)
+		g := graph.RandomTree(n, r)
+		h := graph.RandomTree(n, r)
+		if i%3 == 0 {
+			// h is a relabeling of g
+			pm := r.Perm(n)
+			h = graph.Undirected{make(graph.AdjacencyList, n)}
+			g.Edges(func(e graph.Edge) {
+				h.AddEdge(graph.NI(pm[e.N1]), graph.NI(pm[e.N2]))
+			})
+		}
+		// brute force over all permutations
+		ge := sortedEdges(g)
+		he := sortedEdges(h)
+		want, wantR := false, false
+		p := make([]graph.NI, n)
+		for i := range p {
+			p[i] = graph.NI(i)
+		}
+		var perm func(int)
+		perm = func(k int) {
+			if k == n {
+				for e := range ge {
+					m := graph.Edge{p[e.N1], p[e.N2]}
+					if m.N1 > m.N2 {
+						m.N1, m.N2 = m.N2, m.N1
+					}
+					if !he[m] {
+						return
+					}
+				}
+				want = true
+				if p[0] == 0 {
+					wantR = true
+				}
+				return
+			}
+			for i := k; i < n; i++ {
+				p[k], p[i] = p[i], p[k]
+				perm(k + 1)
+				p[k], p[i] = p[i], p[k]
+			}
+		}
+		perm(0)
+		if got := g.TreeIsomorphic(h); got != want {
+			t.Fatalf("TreeIsomorphic %v %v = %t", g, h, got)
+		}
+		if got := g.RootedTreeIsomorphic(0, h, 0); got != wantR {
+			t.Fatalf("RootedTreeIsomorphic %v %v = %t", g, h, got)
+		}
+	}
+}
+
+func TestHeavyLight(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 100; i++ {
+		// random forest, parents earlier in a random permutation
+		n := 1 + r.Intn(40)
+		pm := r.Perm(n)
+		f := graph.NewFromList(n)
+		for j, v := range pm {
+			f.Paths[v].From = -1
+			if j > 0 && r.Intn(6) > 0 {
+				f.Paths[v].From = graph.NI(pm[r.Intn(j)])
+			}
+		}
+		size := f.SubtreeSizes()
+		h := f.HeavyLight()
+		x := graph.NewLCAIndex(f)
+		// subtrees contiguous
+		for v := 0; v < n; v++ {
+			for k := h.Pos[v]; k < h.Pos[v]+size[v]; k++ {
+				u := h.Order[k]
+				if x.CommonStart(graph.NI(v), u) != graph.NI(v) {
+					t.Fatal("subtree not contiguous")
+				}
+			}
+		}
+		for a := graph.NI(0); int(a) < n; a++ {
+			for b := graph.NI(0); int(b) < n; b++ {
+				segs, lca := h.Segments(a, b)
+				if lca != x.CommonStart(a, b) {
+					t.Fatalf("Segments(%d, %d) lca %d", a, b, lca)
+				}
+				if lca < 0 {
+					continue
+				}
+				// segments cover exactly the path nodes
+				on := map[graph.NI]bool{}
+				for _, s := range segs {
+					for k := s[0]; k <= s[1]; k++ {
+						if on[h.Order[k]] {
+							t.Fatal("node covered twice")
+						}
+						on[h.Order[k]] = true
+					}
+				}
+				if len(on) != x.Distance(a, b)+1 {
+					t.Fatalf("Segments(%d, %d) = %v", a, b, segs)
+				}
+				for v := range on {
+					if x.Distance(a, v)+x.Distance(v, b) != x.Distance(a, b) {
+						t.Fatal("node not on path")
+					}
+				}
+			}
+		}
+	}
+}
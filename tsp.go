@@ -0,0 +1,398 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/soniakeys/bits"
+)
+
+// tsp.go has traveling salesman algorithms on a DistanceMatrix, and tour
+// validation on a DistanceMatrix or a LabeledUndirected.
+//
+// A tour is a []NI listing each node of the distance matrix exactly once.
+// The tour is closed, the last node connecting back to the first.
+// Distance matrix elements of +Inf represent missing arcs.  For a graph
+// that is not complete, a DistanceMatrix method such as
+// LabeledAdjacencyList.DistanceMatrix gives arc weights, and
+// FloydWarshall then gives a complete metric closure, although then tours
+// represent walks that may pass through nodes more than once.
+
+// TourDistance validates a tour and returns its total distance.
+//
+// Tour must list each node of d exactly once and each arc of the tour,
+// including the arc from the last node back to the first, must have a
+// finite distance.  If the tour is not valid, a non-nil error describes
+// the problem.
+func (d DistanceMatrix) TourDistance(tour []NI) (dist float64, err error) {
+	if err := checkTourNodes(len(d), tour); err != nil {
+		return math.Inf(1), err
+	}
+	fr := tour[len(tour)-1]
+	for _, to := range tour {
+		if math.IsInf(d[fr][to], 1) {
+			return math.Inf(1), fmt.Errorf("no arc %d->%d", fr, to)
+		}
+		fr = to
+	}
+	return d.tourDist(tour), nil
+}
+
+// TourDistance validates a tour against g and returns its total distance.
+//
+// Tour must list each node of g exactly once and each step of the tour,
+// including the step from the last node back to the first, must follow an
+// edge of g.  Edge weights are given by WeightFunc w.  Where parallel edges
+// join two nodes, the least weight is used.  A tour of a single node has
+// distance 0.  If the tour is not valid, a non-nil error describes the
+// problem.
+func (g LabeledUndirected) TourDistance(tour []NI, w WeightFunc) (dist float64, err error) {
+	a := g.LabeledAdjacencyList
+	if err := checkTourNodes(len(a), tour); err != nil {
+		return math.Inf(1), err
+	}
+	if len(tour) < 2 {
+		return 0, nil
+	}
+	fr := tour[len(tour)-1]
+	for _, to := range tour {
+		min := math.Inf(1)
+		found := false
+		for _, h := range a[fr] {
+			if h.To == to {
+				found = true
+				if wt := w(h.Label); wt < min {
+					min = wt
+				}
+			}
+		}
+		if !found {
+			return math.Inf(1), fmt.Errorf("no edge %d-%d", fr, to)
+		}
+		dist += min
+		fr = to
+	}
+	return dist, nil
+}
+
+// checkTourNodes checks that tour lists each of nodes 0..n-1 exactly once.
+func checkTourNodes(n int, tour []NI) error {
+	if len(tour) != n {
+		return fmt.Errorf("tour has %d nodes, want %d", len(tour), n)
+	}
+	seen := bits.New(n)
+	for _, nd := range tour {
+		if nd < 0 || int(nd) >= n {
+			return fmt.Errorf("NI %d not in graph", nd)
+		}
+		if seen.Bit(int(nd)) == 1 {
+			return fmt.Errorf("node %d visited twice", nd)
+		}
+		seen.SetBit(int(nd), 1)
+	}
+	return nil
+}
+
+// tourDist returns the total distance of a tour without validation.
+func (d DistanceMatrix) tourDist(tour []NI) (dist float64) {
+	if len(tour) < 2 {
+		return 0
+	}
+	fr := tour[len(tour)-1]
+	for _, to := range tour {
+		dist += d[fr][to]
+		fr = to
+	}
+	return
+}
+
+// NearestNeighborTour constructs a tour by starting at node start and
+// repeatedly visiting the nearest unvisited node.
+//
+// If d has +Inf elements, the returned tour may use missing arcs,
+// in which case dist will be +Inf.
+func (d DistanceMatrix) NearestNeighborTour(start NI) (tour []NI, dist float64) {
+	if len(d) == 0 {
+		return nil, 0
+	}
+	visited := bits.New(len(d))
+	tour = make([]NI, 1, len(d))
+	tour[0] = start
+	visited.SetBit(int(start), 1)
+	for c := start; len(tour) < len(d); {
+		nx := NI(-1)
+		min := math.Inf(1)
+		for to, dt := range d[c] {
+			if visited.Bit(to) == 0 && (nx < 0 || dt < min) {
+				nx = NI(to)
+				min = dt
+			}
+		}
+		visited.SetBit(int(nx), 1)
+		tour = append(tour, nx)
+		c = nx
+	}
+	return tour, d.tourDist(tour)
+}
+
+// Christofides constructs a tour with the algorithm of Christofides.
+//
+// A minimum spanning tree is augmented with a minimum weight perfect
+// matching on its odd degree nodes, an Eulerian cycle of the result is
+// found, and repeated nodes are skipped.
+//
+// D should be symmetric.  If it also satisfies the triangle inequality,
+// the tour is at most 3/2 the length of an optimal tour.
+//
+// If d has +Inf elements, Christofides may fail to construct a tour, in
+// which case it returns nil, +Inf.
+//
+// Ref: "Worst-case analysis of a new heuristic for the travelling salesman
+// problem", Nicos Christofides, Report 388, Carnegie Mellon University
+// (1976).
+func (d DistanceMatrix) Christofides() (tour []NI, dist float64) {
+	n := len(d)
+	if n < 3 {
+		for i := range d {
+			tour = append(tour, NI(i))
+		}
+		return tour, d.tourDist(tour)
+	}
+	// Prim's algorithm on the dense matrix
+	key := make([]float64, n)
+	from := make([]NI, n)
+	inTree := bits.New(n)
+	for i := range key {
+		key[i] = math.Inf(1)
+		from[i] = -1
+	}
+	key[0] = 0
+	var g Undirected
+	g.AdjacencyList = make(AdjacencyList, n)
+	for range d {
+		u := -1
+		for v := range key {
+			if inTree.Bit(v) == 0 && (u < 0 || key[v] < key[u]) {
+				u = v
+			}
+		}
+		if math.IsInf(key[u], 1) {
+			return nil, math.Inf(1)
+		}
+		inTree.SetBit(u, 1)
+		if from[u] >= 0 {
+			g.AddEdge(from[u], NI(u))
+		}
+		for v, dv := range d[u] {
+			if inTree.Bit(v) == 0 && dv < key[v] {
+				key[v] = dv
+				from[v] = NI(u)
+			}
+		}
+	}
+	// minimum weight perfect matching on odd degree nodes
+	var odd []NI
+	for v, to := range g.AdjacencyList {
+		if len(to)%2 == 1 {
+			odd = append(odd, NI(v))
+		}
+	}
+	var edges []matchEdge
+	for i, ni := range odd {
+		for j := i + 1; j < len(odd); j++ {
+			if dij := d[ni][odd[j]]; !math.IsInf(dij, 1) {
+				edges = append(edges, matchEdge{i, j, dij})
+			}
+		}
+	}
+	mate := minWeightPerfectMatching(len(odd), edges)
+	for i, j := range mate {
+		switch {
+		case j < 0:
+			return nil, math.Inf(1)
+		case i < j:
+			g.AddEdge(odd[i], odd[j])
+		}
+	}
+	// Eulerian cycle, shortcut to a tour
+	c, err := g.EulerianCycle()
+	if err != nil {
+		return nil, math.Inf(1)
+	}
+	visited := bits.New(n)
+	tour = make([]NI, 0, n)
+	for _, v := range c {
+		if visited.Bit(int(v)) == 0 {
+			visited.SetBit(int(v), 1)
+			tour = append(tour, v)
+		}
+	}
+	return tour, d.tourDist(tour)
+}
+
+// TwoOpt improves a tour in place by 2-opt moves, reversing sections of
+// the tour while that shortens it.
+//
+// D should be symmetric.  The result is the distance of the improved tour.
+//
+// Ref: "A Method for Solving Traveling-Salesman Problems", G. A. Croes,
+// Operations Research (1958).
+func (d DistanceMatrix) TwoOpt(tour []NI) (dist float64) {
+	n := len(tour)
+	for improved := true; improved; {
+		improved = false
+		for i := 0; i < n-2; i++ {
+			a, b := tour[i], tour[i+1]
+			for j := i + 2; j < n; j++ {
+				c, e := tour[j], tour[(j+1)%n]
+				if e == a {
+					continue
+				}
+				// replace arcs a-b, c-e with a-c, b-e
+				if d[a][c]+d[b][e] < d[a][b]+d[c][e] {
+					for l, r := i+1, j; l < r; l, r = l+1, r-1 {
+						tour[l], tour[r] = tour[r], tour[l]
+					}
+					b = tour[i+1]
+					improved = true
+				}
+			}
+		}
+	}
+	return d.tourDist(tour)
+}
+
+// OrOpt improves a tour in place by Or-opt moves, relocating segments of
+// one to three consecutive nodes to other positions in the tour while that
+// shortens it.
+//
+// Segments are moved without reversal so D need not be symmetric.
+// The result is the distance of the improved tour.
+//
+// Ref: "Traveling Salesman-Type Combinatorial Problems and their Relation
+// to the Logistics of Regional Blood Banking", Ilhan Or, PhD thesis,
+// Northwestern University (1976).
+func (d DistanceMatrix) OrOpt(tour []NI) (dist float64) {
+	n := len(tour)
+	buf := make([]NI, 0, n)
+	for improved := true; improved; {
+		improved = false
+		for sl := 1; sl <= 3 && sl+2 < n; sl++ {
+			for i := 0; i+sl <= n; i++ {
+				p := tour[(i-1+n)%n]
+				s0, s1 := tour[i], tour[i+sl-1]
+				nx := tour[(i+sl)%n]
+				best := -1
+				var bestGain float64
+				for k := 0; k < n; k++ {
+					// skip arcs touching the segment
+					if (k-i+1+n)%n <= sl {
+						continue
+					}
+					x, y := tour[k], tour[(k+1)%n]
+					before := d[p][s0] + d[s1][nx] + d[x][y]
+					after := d[x][s0] + d[s1][y] + d[p][nx]
+					if g := before - after; after < before && g > bestGain {
+						best = k
+						bestGain = g
+					}
+				}
+				if best < 0 {
+					continue
+				}
+				// rebuild tour with the segment after tour[best]
+				buf = buf[:0]
+				for k := 0; k < n; k++ {
+					if k >= i && k < i+sl {
+						continue
+					}
+					buf = append(buf, tour[k])
+					if k == best {
+						buf = append(buf, tour[i:i+sl]...)
+					}
+				}
+				copy(tour, buf)
+				improved = true
+			}
+		}
+	}
+	return d.tourDist(tour)
+}
+
+// HeldKarp finds an optimal tour by the dynamic programming algorithm of
+// Held and Karp.
+//
+// Time is O(2ⁿn²) and memory is O(2ⁿn) so HeldKarp is limited to
+// distance matrices of at most 20 nodes.  A larger d returns a non-nil
+// error.
+//
+// If no tour exists with finite distance, the result is nil, +Inf.
+//
+// Ref: "A Dynamic Programming Approach to Sequencing Problems", Michael
+// Held and Richard M. Karp, Journal of SIAM (1962).
+func (d DistanceMatrix) HeldKarp() (tour []NI, dist float64, err error) {
+	n := len(d)
+	switch {
+	case n > 20:
+		return nil, math.Inf(1), errors.New("HeldKarp limited to 20 nodes")
+	case n < 2:
+		for i := range d {
+			tour = append(tour, NI(i))
+		}
+		return tour, 0, nil
+	}
+	// paths start at node 0.  subsets are of nodes 1..n-1, represented
+	// with bit j for node j+1.
+	m := n - 1
+	full := 1 << uint(m)
+	inf := math.Inf(1)
+	cost := make([]float64, full*m)
+	prev := make([]int8, full*m)
+	for i := range cost {
+		cost[i] = inf
+	}
+	for j := 0; j < m; j++ {
+		cost[(1<<uint(j))*m+j] = d[0][j+1]
+	}
+	for s := 1; s < full; s++ {
+		for j := 0; j < m; j++ {
+			cj := cost[s*m+j]
+			if s&(1<<uint(j)) == 0 || math.IsInf(cj, 1) {
+				continue
+			}
+			dj := d[j+1]
+			for k := 0; k < m; k++ {
+				if s&(1<<uint(k)) != 0 {
+					continue
+				}
+				x := (s|1<<uint(k))*m + k
+				if c := cj + dj[k+1]; c < cost[x] {
+					cost[x] = c
+					prev[x] = int8(j)
+				}
+			}
+		}
+	}
+	s := full - 1
+	end := -1
+	dist = inf
+	for j := 0; j < m; j++ {
+		if c := cost[s*m+j] + d[j+1][0]; c < dist {
+			dist = c
+			end = j
+		}
+	}
+	if end < 0 {
+		return nil, inf, nil
+	}
+	tour = make([]NI, n)
+	for i, j := n-1, end; i > 0; i-- {
+		tour[i] = NI(j + 1)
+		s, j = s&^(1<<uint(j)), int(prev[s*m+j])
+	}
+	return tour, dist, nil
+}
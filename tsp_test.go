@@ -0,0 +1,276 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+// tspPoints returns the Euclidean distance matrix of points.
+func tspPoints(pts [][2]float64) graph.DistanceMatrix {
+	d := make(graph.DistanceMatrix, len(pts))
+	for i, p := range pts {
+		d[i] = make([]float64, len(pts))
+		for j, q := range pts {
+			d[i][j] = math.Hypot(p[0]-q[0], p[1]-q[1])
+		}
+	}
+	return d
+}
+
+func tspExample() graph.DistanceMatrix {
+	// 4 . . 3 . . 2
+	// . . . . . . .
+	// . . . . . . .
+	// . . . 5 . . .
+	// . . . . . . .
+	// . . . . . . .
+	// 0 . . . . . 1
+	return tspPoints([][2]float64{
+		{0, 0}, {6, 0}, {6, 6}, {3, 6}, {0, 6}, {3, 3}})
+}
+
+func ExampleDistanceMatrix_NearestNeighborTour() {
+	d := tspExample()
+	tour, dist := d.NearestNeighborTour(0)
+	fmt.Printf("%v %.2f\n", tour, dist)
+	// Output:
+	// [0 5 3 2 1 4] 30.73
+}
+
+func ExampleDistanceMatrix_Christofides() {
+	d := tspExample()
+	tour, dist := d.Christofides()
+	fmt.Printf("%v %.2f\n", tour, dist)
+	// Output:
+	// [0 5 2 3 4 1] 28.97
+}
+
+func ExampleDistanceMatrix_TwoOpt() {
+	d := tspExample()
+	tour, _ := d.NearestNeighborTour(0)
+	dist := d.TwoOpt(tour)
+	fmt.Printf("%v %.2f\n", tour, dist)
+	// Output:
+	// [0 1 2 3 4 5] 26.49
+}
+
+func ExampleDistanceMatrix_OrOpt() {
+	d := tspExample()
+	tour, _ := d.NearestNeighborTour(0)
+	dist := d.OrOpt(tour)
+	fmt.Printf("%v %.2f\n", tour, dist)
+	// Output:
+	// [5 4 3 2 1 0] 26.49
+}
+
+func ExampleDistanceMatrix_HeldKarp() {
+	d := tspExample()
+	tour, dist, err := d.HeldKarp()
+	fmt.Printf("%v %.2f %v\n", tour, dist, err)
+	// Output:
+	// [0 4 3 2 5 1] 26.49 <nil>
+}
+
+func ExampleDistanceMatrix_TourDistance() {
+	// 0--1
+	// |  |
+	// 3--2
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{1, 2}, 0)
+	g.AddEdge(graph.Edge{2, 3}, 0)
+	g.AddEdge(graph.Edge{3, 0}, 0)
+	d := g.DistanceMatrix(func(graph.LI) float64 { return 1 })
+	fmt.Println(d.TourDistance([]graph.NI{0, 1, 2, 3}))
+	fmt.Println(d.TourDistance([]graph.NI{0, 2, 1, 3}))
+	fmt.Println(d.TourDistance([]graph.NI{0, 1, 2, 2}))
+	// Output:
+	// 4 <nil>
+	// +Inf no arc 0->2
+	// +Inf node 2 visited twice
+}
+
+func ExampleLabeledUndirected_TourDistance() {
+	// 0--1
+	// |  |
+	// 3--2
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 0)
+	g.AddEdge(graph.Edge{1, 2}, 1)
+	g.AddEdge(graph.Edge{2, 3}, 2)
+	g.AddEdge(graph.Edge{3, 0}, 3)
+	w := func(l graph.LI) float64 { return float64(l + 1) }
+	fmt.Println(g.TourDistance([]graph.NI{0, 1, 2, 3}, w))
+	fmt.Println(g.TourDistance([]graph.NI{0, 2, 1, 3}, w))
+	fmt.Println(g.TourDistance([]graph.NI{0, 1, 2}, w))
+	// Output:
+	// 10 <nil>
+	// +Inf no edge 0-2
+	// +Inf tour has 3 nodes, want 4
+}
+
+func TestLabeledUndirectedTourDistance(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	nValid := 0
+	for i := 0; i < 500; i++ {
+		n := 1 + r.Intn(6)
+		var g graph.LabeledUndirected
+		g.LabeledAdjacencyList = make(graph.LabeledAdjacencyList, n)
+		for j := r.Intn(3 * n); j > 0; j-- {
+			// random edges, possibly parallel
+			n1, n2 := graph.NI(r.Intn(n)), graph.NI(r.Intn(n))
+			if n1 != n2 {
+				g.AddEdge(graph.Edge{n1, n2}, graph.LI(r.Intn(10)))
+			}
+		}
+		w := func(l graph.LI) float64 { return float64(l) }
+		d := g.DistanceMatrix(w)
+		tour := make([]graph.NI, n)
+		for j, p := range r.Perm(n) {
+			tour[j] = graph.NI(p)
+		}
+		got, err := g.TourDistance(tour, w)
+		want, wantErr := d.TourDistance(tour)
+		if (err == nil) != (wantErr == nil) || got != want {
+			t.Fatalf("tour %v: got %v, %v, want %v, %v",
+				tour, got, err, want, wantErr)
+		}
+		if err == nil {
+			nValid++
+		}
+		// nodes not listed exactly once
+		if n > 1 {
+			tour[0] = tour[1]
+			if _, err := g.TourDistance(tour, w); err == nil {
+				t.Fatal("repeated node accepted")
+			}
+		}
+		if _, err := g.TourDistance(tour[1:], w); err == nil {
+			t.Fatal("short tour accepted")
+		}
+	}
+	if nValid == 0 {
+		t.Fatal("no valid tours tested")
+	}
+}
+
+// tspBrute returns the optimal tour distance by trying all permutations.
+func tspBrute(d graph.DistanceMatrix) float64 {
+	tour := make([]graph.NI, len(d))
+	for i := range tour {
+		tour[i] = graph.NI(i)
+	}
+	best := math.Inf(1)
+	var perm func(int)
+	perm = func(k int) {
+		if k == len(tour) {
+			if dist, err := d.TourDistance(tour); err == nil && dist < best {
+				best = dist
+			}
+			return
+		}
+		for i := k; i < len(tour); i++ {
+			tour[k], tour[i] = tour[i], tour[k]
+			perm(k + 1)
+			tour[k], tour[i] = tour[i], tour[k]
+		}
+	}
+	perm(1)
+	return best
+}
+
+func TestHeldKarp(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(8)
+		d := make(graph.DistanceMatrix, n)
+		for i := range d {
+			d[i] = make([]float64, n)
+			for j := range d[i] {
+				switch {
+				case i == j:
+				case r.Intn(4) == 0:
+					d[i][j] = math.Inf(1)
+				default:
+					d[i][j] = float64(r.Intn(100))
+				}
+			}
+		}
+		tour, dist, err := d.HeldKarp()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := tspBrute(d)
+		if math.IsInf(want, 1) {
+			if tour != nil || !math.IsInf(dist, 1) {
+				t.Fatal("HeldKarp found tour where none exists")
+			}
+			continue
+		}
+		got, err := d.TourDistance(tour)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != dist || dist != want {
+			t.Fatalf("HeldKarp = %v, %v, want %v", tour, dist, want)
+		}
+	}
+	if _, _, err := make(graph.DistanceMatrix, 21).HeldKarp(); err == nil {
+		t.Fatal("HeldKarp accepted 21 nodes")
+	}
+}
+
+func TestTSPHeuristics(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	for i := 0; i < 200; i++ {
+		n := 1 + r.Intn(11)
+		if i%10 == 0 {
+			n = 40 + r.Intn(40)
+		}
+		pts := make([][2]float64, n)
+		for i := range pts {
+			pts[i] = [2]float64{r.Float64(), r.Float64()}
+		}
+		d := tspPoints(pts)
+		opt := 0.
+		if n <= 12 {
+			_, opt, _ = d.HeldKarp()
+		}
+		nn, nnDist := d.NearestNeighborTour(graph.NI(r.Intn(n)))
+		ch, chDist := d.Christofides()
+		for j, tour := range [][]graph.NI{nn, ch} {
+			if dist, err := d.TourDistance(tour); err != nil {
+				t.Fatal(err)
+			} else if dist != []float64{nnDist, chDist}[j] {
+				t.Fatal("wrong tour distance")
+			}
+		}
+		if chDist > 1.5*opt+1e-9 && n <= 12 {
+			t.Fatalf("Christofides %v > 3/2 optimal %v", chDist, opt)
+		}
+		for _, tour := range [][]graph.NI{nn, ch} {
+			dist, _ := d.TourDistance(tour)
+			two := append([]graph.NI{}, tour...)
+			or := append([]graph.NI{}, tour...)
+			for j, improved := range []float64{d.TwoOpt(two), d.OrOpt(or)} {
+				imp := [][]graph.NI{two, or}[j]
+				if got, err := d.TourDistance(imp); err != nil {
+					t.Fatal(err)
+				} else if math.Abs(got-improved) > 1e-9 {
+					t.Fatal("wrong improved distance")
+				}
+				if improved > dist+1e-9 || improved < opt-1e-9 {
+					t.Fatalf("improved %v from %v, optimal %v",
+						improved, dist, opt)
+				}
+			}
+		}
+	}
+}
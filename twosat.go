@@ -0,0 +1,149 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+import "sort"
+
+// twosat.go has a 2-satisfiability solver.
+
+// TwoSAT builds and solves 2-satisfiability problems.
+//
+// A problem is a conjunction of clauses, each the disjunction of two
+// literals.  Variables are numbered from 0.  Variable v has two literals,
+// 2v representing v true and 2v+1 representing v false.  The negation of
+// literal l is l^1.  See Lit.
+//
+// Clauses are represented in an implication graph G with a node for each
+// literal.  Clause a or b is represented by the arcs ^a->b and ^b->a.
+// The problem is unsatisfiable exactly when some literal and its negation
+// are in the same strongly connected component of G.
+//
+// The zero value is an empty problem ready to use.  Variables are added
+// as needed by AddClause.
+//
+// Ref: "A linear-time algorithm for testing the truth of certain quantified
+// boolean formulas", Bengt Aspvall, Michael F. Plass, and Robert Endre
+// Tarjan, Information Processing Letters (1979).
+type TwoSAT struct {
+	G       Directed // implication graph on literals
+	Clauses [][2]NI  // clauses in the order added
+	clause  [][]int  // clause index of each arc, parallel to G
+}
+
+// Lit returns the literal for variable v having the given value.
+func (s *TwoSAT) Lit(v int, value bool) NI {
+	if value {
+		return NI(2 * v)
+	}
+	return NI(2*v + 1)
+}
+
+// NumVars returns the number of variables in the problem.
+func (s *TwoSAT) NumVars() int {
+	return s.G.Order() / 2
+}
+
+// AddClause adds the clause a or b.
+//
+// A unit clause requiring literal a can be added as AddClause(a, a).
+func (s *TwoSAT) AddClause(a, b NI) {
+	n := a
+	if b > n {
+		n = b
+	}
+	for n |= 1; s.G.Order() <= int(n); {
+		s.G.AdjacencyList = append(s.G.AdjacencyList, nil)
+		s.clause = append(s.clause, nil)
+	}
+	c := len(s.Clauses)
+	s.Clauses = append(s.Clauses, [2]NI{a, b})
+	s.addArc(a^1, b, c)
+	if a != b {
+		s.addArc(b^1, a, c)
+	}
+}
+
+// AddImplication adds the clause a implies b, which is ^a or b.
+func (s *TwoSAT) AddImplication(a, b NI) {
+	s.AddClause(a^1, b)
+}
+
+func (s *TwoSAT) addArc(fr, to NI, c int) {
+	s.G.AdjacencyList[fr] = append(s.G.AdjacencyList[fr], to)
+	s.clause[fr] = append(s.clause[fr], c)
+}
+
+// Solve finds a satisfying assignment or an unsatisfiable core.
+//
+// If the problem is satisfiable, assign holds a value for each variable and
+// core is nil.  Otherwise assign is nil and core is a list of indexes into
+// s.Clauses of an unsatisfiable subset of the clauses, in ascending order.
+// The core consists of clauses on a chain of implications from some literal
+// to its negation and back.  It is not necessarily minimal.
+//
+// Solve takes time linear in the number of variables and clauses.
+func (s *TwoSAT) Solve() (assign []bool, core []int) {
+	a := s.G.AdjacencyList
+	// components are emitted in reverse topological order
+	comp := make([]int, len(a))
+	nc := 0
+	s.G.StronglyConnectedComponents(func(c []NI) bool {
+		for _, n := range c {
+			comp[n] = nc
+		}
+		nc++
+		return true
+	})
+	assign = make([]bool, len(a)/2)
+	for v := range assign {
+		p, n := comp[2*v], comp[2*v+1]
+		if p == n {
+			return nil, s.core(NI(2*v), comp)
+		}
+		// choose the literal later in topological order
+		assign[v] = p < n
+	}
+	return assign, nil
+}
+
+// core returns clauses on implication paths from literal l to ^l and back.
+// Both literals must be in the same component.
+func (s *TwoSAT) core(l NI, comp []int) []int {
+	a := s.G.AdjacencyList
+	type arc struct {
+		fr NI
+		x  int
+	}
+	from := make([]arc, len(a))
+	in := map[int]bool{}
+	path := func(start, end NI) {
+		for i := range from {
+			from[i].fr = -1
+		}
+		from[start].fr = start
+		q := []NI{start}
+		for len(q) > 0 && from[end].fr < 0 {
+			n := q[0]
+			q = q[1:]
+			for x, to := range a[n] {
+				if from[to].fr < 0 && comp[to] == comp[start] {
+					from[to] = arc{n, x}
+					q = append(q, to)
+				}
+			}
+		}
+		for n := end; n != start; n = from[n].fr {
+			f := from[n]
+			in[s.clause[f.fr][f.x]] = true
+		}
+	}
+	path(l, l^1)
+	path(l^1, l)
+	core := make([]int, 0, len(in))
+	for c := range in {
+		core = append(core, c)
+	}
+	sort.Ints(core)
+	return core
+}
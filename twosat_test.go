@@ -0,0 +1,94 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleTwoSAT() {
+	// (x0 or x1) and (not x0 or x2) and (not x1 or not x2) and not x2
+	var s graph.TwoSAT
+	s.AddClause(s.Lit(0, true), s.Lit(1, true))
+	s.AddClause(s.Lit(0, false), s.Lit(2, true))
+	s.AddClause(s.Lit(1, false), s.Lit(2, false))
+	s.AddClause(s.Lit(2, false), s.Lit(2, false))
+	fmt.Println(s.Solve())
+	// add x0, making the problem unsatisfiable
+	s.AddClause(s.Lit(0, true), s.Lit(0, true))
+	fmt.Println(s.Solve())
+	// Output:
+	// [false true false] []
+	// [] [1 3 4]
+}
+
+func TestTwoSAT(t *testing.T) {
+	r := rand.New(rand.NewSource(11))
+	sat := func(c [2]graph.NI, assign []bool) bool {
+		return assign[c[0]/2] == (c[0]%2 == 0) || assign[c[1]/2] == (c[1]%2 == 0)
+	}
+	nSat, nUnsat := 0, 0
+	for i := 0; i < 500; i++ {
+		nv := 1 + r.Intn(8)
+		nc := r.Intn(3 * nv)
+		var s graph.TwoSAT
+		for j := 0; j < nc; j++ {
+			s.AddClause(graph.NI(r.Intn(2*nv)), graph.NI(r.Intn(2*nv)))
+		}
+		// brute force
+		n := s.NumVars()
+		want := false
+		a := make([]bool, n)
+		for m := 0; m < 1<<uint(n) && !want; m++ {
+			for v := range a {
+				a[v] = m&(1<<uint(v)) != 0
+			}
+			ok := true
+			for _, c := range s.Clauses {
+				if !sat(c, a) {
+					ok = false
+					break
+				}
+			}
+			want = ok
+		}
+		assign, core := s.Solve()
+		if (assign != nil) != want {
+			t.Fatalf("clauses %v: got %v, want satisfiable %t", s.Clauses, assign, want)
+		}
+		if want {
+			nSat++
+			for _, c := range s.Clauses {
+				if !sat(c, assign) {
+					t.Fatalf("clauses %v: assignment %v fails %v", s.Clauses, assign, c)
+				}
+			}
+			continue
+		}
+		nUnsat++
+		// core must be unsatisfiable by itself
+		for m := 0; m < 1<<uint(n); m++ {
+			for v := range a {
+				a[v] = m&(1<<uint(v)) != 0
+			}
+			ok := true
+			for _, x := range core {
+				if !sat(s.Clauses[x], a) {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				t.Fatalf("core %v satisfied by %v", core, a)
+			}
+		}
+	}
+	if nSat == 0 || nUnsat == 0 {
+		t.Fatal("test cases not varied", nSat, nUnsat)
+	}
+}
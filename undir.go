@@ -108,6 +108,113 @@ func Density(n, m int) float64 {
 	return float64(m) * 2 / (float64(n) * float64(n-1))
 }
 
+// Bridges finds the bridges of an undirected graph.
+//
+// A bridge is an edge whose removal would increase the number of connected
+// components of the graph.  The method calls emit for each bridge found,
+// as long as emit returns true.  If emit returns false, Bridges returns
+// immediately.
+//
+// Parallel edges are handled correctly.  That is, parallel edges are never
+// bridges.  Loops are never bridges.
+//
+// See also the equivalent labeled Bridges, and TwoEdgeConnectedComponents.
+func (g Undirected) Bridges(emit func(Edge) bool) {
+	parent, _, bridge := g.bridgeDFS()
+	bridge.IterateOnes(func(n int) bool {
+		return emit(Edge{parent[n], NI(n)})
+	})
+}
+
+// BridgeTree constructs the bridge tree of an undirected graph.
+//
+// Nodes of the bridge tree are the 2-edge-connected components of g and
+// edges of the bridge tree are the bridges of g.  Component numbers ci are
+// as returned by TwoEdgeConnectedComponents.  As those are 1-based,
+// component c is represented by node c-1 of bridge tree t.  A bridge of g
+// from node n1 to n2 is represented in t by an edge from node ci[n1]-1 to
+// ci[n2]-1.
+//
+// If g is not connected, t will be a forest, with a tree for each connected
+// component of g.
+//
+// See also the equivalent labeled BridgeTree.
+func (g Undirected) BridgeTree() (t Undirected, ci []int) {
+	parent, order, bridge := g.bridgeDFS()
+	ci, nc := teccInts(parent, order, bridge)
+	t.AdjacencyList = make(AdjacencyList, nc)
+	bridge.IterateOnes(func(n int) bool {
+		t.AddEdge(NI(ci[parent[n]]-1), NI(ci[n]-1))
+		return true
+	})
+	return
+}
+
+// bridgeDFS is the depth-first search common to bridge and 2-edge-connected
+// component methods.
+//
+// Returned is the DFS forest as parent, with -1 for roots, nodes in DFS
+// preorder, and bits set for nodes n where the tree edge from parent[n] to
+// n is a bridge.
+func (g Undirected) bridgeDFS() (parent, order []NI, bridge bits.Bits) {
+	a := g.AdjacencyList
+	number := make([]int, len(a))
+	low := make([]int, len(a))
+	parent = make([]NI, len(a))
+	order = make([]NI, 0, len(a))
+	bridge = bits.New(len(a))
+	var df func(NI)
+	df = func(v NI) {
+		order = append(order, v)
+		number[v] = len(order)
+		low[v] = len(order)
+		// one arc back to the parent is the tree edge.  any others are
+		// parallel edges.
+		treeArc := true
+		for _, w := range a[v] {
+			switch {
+			case number[w] == 0:
+				parent[w] = v
+				df(w)
+				if low[w] < low[v] {
+					low[v] = low[w]
+				}
+				if low[w] > number[v] {
+					bridge.SetBit(int(w), 1)
+				}
+			case w == parent[v] && treeArc:
+				treeArc = false
+			case number[w] < low[v]:
+				low[v] = number[w]
+			}
+		}
+	}
+	for n := range a {
+		if number[n] == 0 {
+			parent[n] = -1
+			df(NI(n))
+		}
+	}
+	return
+}
+
+// teccInts numbers 2-edge-connected components from the result of bridgeDFS.
+//
+// A 2-edge-connected component is a subtree of the DFS forest, rooted at a
+// DFS root or at the lower end point of a bridge.
+func teccInts(parent, order []NI, bridge bits.Bits) (ci []int, nc int) {
+	ci = make([]int, len(parent))
+	for _, n := range order {
+		if p := parent[n]; p < 0 || bridge.Bit(int(n)) == 1 {
+			nc++
+			ci[n] = nc
+		} else {
+			ci[n] = ci[p]
+		}
+	}
+	return
+}
+
 // An EdgeVisitor is an argument to some traversal methods.
 //
 // Traversal methods call the visitor function for each edge visited.
@@ -420,6 +527,24 @@ func (g Undirected) BlockCut(block func([]Edge) bool, cut func(NI) bool, isolate
 	}
 }
 
+// TwoEdgeConnectedComponents finds the 2-edge-connected components of an
+// undirected graph.
+//
+// A 2-edge-connected component is a maximal subgraph that remains
+// connected if any single edge is removed.  The 2-edge-connected components
+// of a graph are the connected components that remain after removing all
+// bridges.
+//
+// The method assigns numbers to components 1-based, 1 through the number of
+// components.  Return value ci contains the component number for each node.
+// Return value nc is the number of components.
+//
+// See also the equivalent labeled TwoEdgeConnectedComponents, Bridges, and
+// BridgeTree.
+func (g Undirected) TwoEdgeConnectedComponents() (ci []int, nc int) {
+	return teccInts(g.bridgeDFS())
+}
+
 // AddEdge adds an edge to a labeled graph.
 //
 // It can be useful for constructing undirected graphs.
@@ -455,6 +580,93 @@ func (p *LabeledUndirected) AddEdge(e Edge, l LI) {
 	}
 }
 
+// Bridges finds the bridges of a labeled undirected graph.
+//
+// A bridge is an edge whose removal would increase the number of connected
+// components of the graph.  The method calls emit for each bridge found,
+// as long as emit returns true.  If emit returns false, Bridges returns
+// immediately.
+//
+// Parallel edges are handled correctly, regardless of their labels.  That
+// is, parallel edges are never bridges.  Loops are never bridges.
+//
+// See also the equivalent unlabeled Bridges, and TwoEdgeConnectedComponents.
+func (g LabeledUndirected) Bridges(emit func(LabeledEdge) bool) {
+	parent, labels, _, bridge := g.bridgeDFS()
+	bridge.IterateOnes(func(n int) bool {
+		return emit(LabeledEdge{Edge{parent[n], NI(n)}, labels[n]})
+	})
+}
+
+// BridgeTree constructs the bridge tree of a labeled undirected graph.
+//
+// Nodes of the bridge tree are the 2-edge-connected components of g and
+// edges of the bridge tree are the bridges of g.  Component numbers ci are
+// as returned by TwoEdgeConnectedComponents.  As those are 1-based,
+// component c is represented by node c-1 of bridge tree t.  A bridge of g
+// from node n1 to n2 is represented in t by an edge from node ci[n1]-1 to
+// ci[n2]-1, with the label of the bridge.
+//
+// If g is not connected, t will be a forest, with a tree for each connected
+// component of g.
+//
+// See also the equivalent unlabeled BridgeTree.
+func (g LabeledUndirected) BridgeTree() (t LabeledUndirected, ci []int) {
+	parent, labels, order, bridge := g.bridgeDFS()
+	ci, nc := teccInts(parent, order, bridge)
+	t.LabeledAdjacencyList = make(LabeledAdjacencyList, nc)
+	bridge.IterateOnes(func(n int) bool {
+		t.AddEdge(Edge{NI(ci[parent[n]] - 1), NI(ci[n] - 1)}, labels[n])
+		return true
+	})
+	return
+}
+
+// bridgeDFS is the labeled version of Undirected.bridgeDFS.  It additionally
+// returns the label of the tree edge to each node.
+func (g LabeledUndirected) bridgeDFS() (parent []NI, labels []LI, order []NI, bridge bits.Bits) {
+	// Code nearly identical to unlabled version.
+	a := g.LabeledAdjacencyList
+	number := make([]int, len(a))
+	low := make([]int, len(a))
+	parent = make([]NI, len(a))
+	labels = make([]LI, len(a))
+	order = make([]NI, 0, len(a))
+	bridge = bits.New(len(a))
+	var df func(NI)
+	df = func(v NI) {
+		order = append(order, v)
+		number[v] = len(order)
+		low[v] = len(order)
+		treeArc := true
+		for _, w := range a[v] {
+			switch {
+			case number[w.To] == 0:
+				parent[w.To] = v
+				labels[w.To] = w.Label
+				df(w.To)
+				if low[w.To] < low[v] {
+					low[v] = low[w.To]
+				}
+				if low[w.To] > number[v] {
+					bridge.SetBit(int(w.To), 1)
+				}
+			case w.To == parent[v] && treeArc:
+				treeArc = false
+			case number[w.To] < low[v]:
+				low[v] = number[w.To]
+			}
+		}
+	}
+	for n := range a {
+		if number[n] == 0 {
+			parent[n] = -1
+			df(NI(n))
+		}
+	}
+	return
+}
+
 // A LabeledEdgeVisitor is an argument to some traversal methods.
 //
 // Traversal methods call the visitor function for each edge visited.
@@ -756,6 +968,25 @@ func (g LabeledUndirected) TarjanBiconnectedComponents(emit func([]LabeledEdge)
 	}
 }
 
+// TwoEdgeConnectedComponents finds the 2-edge-connected components of a
+// labeled undirected graph.
+//
+// A 2-edge-connected component is a maximal subgraph that remains
+// connected if any single edge is removed.  The 2-edge-connected components
+// of a graph are the connected components that remain after removing all
+// bridges.
+//
+// The method assigns numbers to components 1-based, 1 through the number of
+// components.  Return value ci contains the component number for each node.
+// Return value nc is the number of components.
+//
+// See also the equivalent unlabeled TwoEdgeConnectedComponents, Bridges, and
+// BridgeTree.
+func (g LabeledUndirected) TwoEdgeConnectedComponents() (ci []int, nc int) {
+	parent, _, order, bridge := g.bridgeDFS()
+	return teccInts(parent, order, bridge)
+}
+
 func (e *eulerian) pushUndir() error {
 	for u := e.top(); ; {
 		e.uv.SetBit(int(u), 0)
@@ -4,6 +4,7 @@
 package graph
 
 import (
+	"context"
 	"errors"
 	"fmt"
 
@@ -1072,6 +1073,183 @@ func (g LabeledUndirected) IsTree(root NI) (isTree, allTree bool) {
 	return true, v.AllZeros()
 }
 
+// MaximumClique finds a maximum clique in an undirected graph.
+//
+// The graph must not contain parallel edges or loops.
+//
+// Where the BronKerbosch methods enumerate all maximal cliques, MaximumClique
+// searches for just a single clique of maximum size.  The algorithm is a
+// branch and bound search with a greedy coloring bound, following Tomita's
+// MCS.  See "A simple and faster branch-and-bound algorithm for finding a
+// maximum clique", Etsuji Tomita et al., WALCOM 2010.
+//
+// The search can be bounded by either argument ctx or argument budget.
+// If ctx is cancelled, or if budget is > 0 and more than budget nodes
+// of the search tree are expanded, the search stops.  The best clique found
+// so far is returned as clique and exact is returned false.  If the search
+// runs to completion, exact is returned true and clique is a maximum clique.
+//
+// There are equivalent labeled and unlabeled versions of this method.
+func (g LabeledUndirected) MaximumClique(ctx context.Context, budget int) (clique bits.Bits, exact bool) {
+	a := g.LabeledAdjacencyList
+	clique = bits.New(len(a))
+	if len(a) == 0 {
+		return clique, true
+	}
+	// adjacency matrix for fast neighbor tests
+	adj := make([]bits.Bits, len(a))
+	for n, to := range a {
+		b := bits.New(len(a))
+		for _, to := range to {
+			if to.To != NI(n) {
+				b.SetBit(int(to.To), 1)
+			}
+		}
+		adj[n] = b
+	}
+	var q, qMax []NI // current and best cliques
+	nExpanded := 0
+	stopped := false
+	// numberSort colors r greedily, returning r reordered by color and the
+	// color number of each node.  Nodes with color numbers too low to
+	// improve on qMax are left at the front of the list with number 0.
+	numberSort := func(r []NI) ([]NI, []int) {
+		nth := len(qMax) - len(q) // threshold
+		if nth < 0 {
+			nth = 0
+		}
+		var c [][]NI // color classes, c[k-1] is class k
+		col := make([]int, len(a))
+		nbIn := func(p NI, ck []NI) (n int, x NI) {
+			for _, v := range ck {
+				if adj[p].Bit(int(v)) == 1 {
+					n++
+					x = v
+				}
+			}
+			return
+		}
+		for _, p := range r {
+			k := 1
+			for ; k <= len(c); k++ {
+				if n, _ := nbIn(p, c[k-1]); n == 0 {
+					break
+				}
+			}
+			if k > len(c) {
+				c = append(c, nil)
+			}
+			c[k-1] = append(c[k-1], p)
+			col[p] = k
+			if k <= nth || k != len(c) {
+				continue
+			}
+			// Re-NUMBER.  try to move p down to a color class at or below
+			// the threshold by swapping a single conflicting node up.
+		renumber:
+			for k1 := 1; k1 < nth; k1++ {
+				n, x := nbIn(p, c[k1-1])
+				if n != 1 {
+					continue
+				}
+				for k2 := k1 + 1; k2 <= nth; k2++ {
+					if n, _ := nbIn(x, c[k2-1]); n > 0 {
+						continue
+					}
+					ck := c[k1-1]
+					for i, v := range ck {
+						if v == x {
+							ck[i] = p
+							break
+						}
+					}
+					c[k2-1] = append(c[k2-1], x)
+					col[p] = k1
+					col[x] = k2
+					// p was the last node added to class k
+					if ck = c[k-1][:len(c[k-1])-1]; len(ck) == 0 {
+						c = c[:k-1]
+					} else {
+						c[k-1] = ck
+					}
+					break renumber
+				}
+			}
+		}
+		// reorder, low colors first
+		s := make([]NI, 0, len(r))
+		no := make([]int, 0, len(r))
+		for _, p := range r {
+			if col[p] <= nth {
+				s = append(s, p)
+				no = append(no, 0)
+			}
+		}
+		for k := nth + 1; k <= len(c); k++ {
+			for _, p := range c[k-1] {
+				s = append(s, p)
+				no = append(no, k)
+			}
+		}
+		return s, no
+	}
+	var expand func(r []NI, no []int)
+	expand = func(r []NI, no []int) {
+		nExpanded++
+		if budget > 0 && nExpanded > budget {
+			stopped = true
+			return
+		}
+		if nExpanded&1023 == 1 && ctx.Err() != nil {
+			stopped = true
+			return
+		}
+		for i := len(r) - 1; i >= 0; i-- {
+			if len(q)+no[i] <= len(qMax) {
+				return
+			}
+			p := r[i]
+			q = append(q, p)
+			var rp []NI
+			for _, v := range r[:i] {
+				if adj[p].Bit(int(v)) == 1 {
+					rp = append(rp, v)
+				}
+			}
+			if len(rp) > 0 {
+				expand(numberSort(rp))
+			} else if len(q) > len(qMax) {
+				qMax = append(qMax[:0], q...)
+			}
+			q = q[:len(q)-1]
+			if stopped {
+				return
+			}
+		}
+	}
+	// initial ordering and numbering
+	r, _ := g.DegeneracyOrdering()
+	maxDeg := 0
+	for _, to := range a {
+		if len(to) > maxDeg {
+			maxDeg = len(to)
+		}
+	}
+	no := make([]int, len(r))
+	for i := range no {
+		if i < maxDeg {
+			no[i] = i + 1
+		} else {
+			no[i] = maxDeg + 1
+		}
+	}
+	expand(r, no)
+	for _, n := range qMax {
+		clique.SetBit(int(n), 1)
+	}
+	return clique, !stopped
+}
+
 // Size returns the number of edges in g.
 //
 // See also ArcSize and AnyLoop.
@@ -11,6 +11,7 @@ package graph_test
 // in the two files as similar as possible.
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
@@ -739,6 +740,24 @@ func ExampleLabeledUndirected_IsTree() {
 	// false false
 }
 
+func ExampleLabeledUndirected_MaximumClique() {
+	// 0--4--5-
+	//    |  | \
+	//    3--2--1
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 4}, 0)
+	g.AddEdge(graph.Edge{4, 5}, 0)
+	g.AddEdge(graph.Edge{4, 3}, 0)
+	g.AddEdge(graph.Edge{3, 2}, 0)
+	g.AddEdge(graph.Edge{5, 2}, 0)
+	g.AddEdge(graph.Edge{5, 1}, 0)
+	g.AddEdge(graph.Edge{2, 1}, 0)
+	c, exact := g.MaximumClique(context.Background(), 0)
+	fmt.Println(c.Slice(), exact)
+	// Output:
+	// [1 2 5] true
+}
+
 func ExampleLabeledUndirected_Size() {
 	//   0--\
 	//  / \-/
@@ -15,6 +15,7 @@ package graph_test
 // in the two files as similar as possible.
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"text/template"
@@ -717,6 +718,24 @@ func ExampleUndirected_IsTree() {
 	// false false
 }
 
+func ExampleUndirected_MaximumClique() {
+	// 0--4--5-
+	//    |  | \
+	//    3--2--1
+	var g graph.Undirected
+	g.AddEdge(0, 4)
+	g.AddEdge(4, 5)
+	g.AddEdge(4, 3)
+	g.AddEdge(3, 2)
+	g.AddEdge(5, 2)
+	g.AddEdge(5, 1)
+	g.AddEdge(2, 1)
+	c, exact := g.MaximumClique(context.Background(), 0)
+	fmt.Println(c.Slice(), exact)
+	// Output:
+	// [1 2 5] true
+}
+
 func ExampleUndirected_Size() {
 	//   0--\
 	//  / \-/
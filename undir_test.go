@@ -4,8 +4,12 @@
 package graph_test
 
 import (
+	"context"
 	"fmt"
+	"math/rand"
+	"testing"
 
+	"github.com/soniakeys/bits"
 	"github.com/soniakeys/graph"
 )
 
@@ -430,6 +434,71 @@ func ExampleUndirected_BlockCut() {
 	// Articulation Point: 1
 }
 
+func ExampleUndirected_Bridges() {
+	// 0---1---2===3   4---5
+	//  \ /
+	//   6
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 6)
+	g.AddEdge(6, 0)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 3) // parallel edge
+	g.AddEdge(4, 5)
+	g.Bridges(func(e graph.Edge) bool {
+		fmt.Println(e)
+		return true
+	})
+	// Output:
+	// {1 2}
+	// {4 5}
+}
+
+func ExampleUndirected_BridgeTree() {
+	// 0---1---2===3   4---5
+	//  \ /
+	//   6
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 6)
+	g.AddEdge(6, 0)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 3) // parallel edge
+	g.AddEdge(4, 5)
+	t, ci := g.BridgeTree()
+	fmt.Println("ci:", ci)
+	fmt.Println("bridge tree:")
+	for n, to := range t.AdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// ci: [1 1 2 2 3 4 1]
+	// bridge tree:
+	// 0 [1]
+	// 1 [0]
+	// 2 [3]
+	// 3 [2]
+}
+
+func ExampleUndirected_TwoEdgeConnectedComponents() {
+	// 0---1---2===3   4---5
+	//  \ /
+	//   6
+	var g graph.Undirected
+	g.AddEdge(0, 1)
+	g.AddEdge(1, 6)
+	g.AddEdge(6, 0)
+	g.AddEdge(1, 2)
+	g.AddEdge(2, 3)
+	g.AddEdge(2, 3) // parallel edge
+	g.AddEdge(4, 5)
+	fmt.Println(g.TwoEdgeConnectedComponents())
+	// Output:
+	// [1 1 2 2 3 4 1] 4
+}
+
 func ExampleLabeledUndirected_AddEdge() {
 	//       --0--
 	//      /     \\6001
@@ -672,6 +741,77 @@ func ExampleLabeledUndirected_TarjanBiconnectedComponents() {
 	// {1 7}
 }
 
+func ExampleLabeledUndirected_Bridges() {
+	// 0---1---2===3   4---5
+	//  \ /
+	//   6
+	//
+	// edges labeled 10 + edge number
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 10)
+	g.AddEdge(graph.Edge{1, 6}, 11)
+	g.AddEdge(graph.Edge{6, 0}, 12)
+	g.AddEdge(graph.Edge{1, 2}, 13)
+	g.AddEdge(graph.Edge{2, 3}, 14)
+	g.AddEdge(graph.Edge{2, 3}, 15) // parallel edge
+	g.AddEdge(graph.Edge{4, 5}, 16)
+	g.Bridges(func(e graph.LabeledEdge) bool {
+		fmt.Println(e)
+		return true
+	})
+	// Output:
+	// {{1 2} 13}
+	// {{4 5} 16}
+}
+
+func ExampleLabeledUndirected_BridgeTree() {
+	// 0---1---2===3   4---5
+	//  \ /
+	//   6
+	//
+	// edges labeled 10 + edge number
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 10)
+	g.AddEdge(graph.Edge{1, 6}, 11)
+	g.AddEdge(graph.Edge{6, 0}, 12)
+	g.AddEdge(graph.Edge{1, 2}, 13)
+	g.AddEdge(graph.Edge{2, 3}, 14)
+	g.AddEdge(graph.Edge{2, 3}, 15) // parallel edge
+	g.AddEdge(graph.Edge{4, 5}, 16)
+	t, ci := g.BridgeTree()
+	fmt.Println("ci:", ci)
+	fmt.Println("bridge tree:")
+	for n, to := range t.LabeledAdjacencyList {
+		fmt.Println(n, to)
+	}
+	// Output:
+	// ci: [1 1 2 2 3 4 1]
+	// bridge tree:
+	// 0 [{1 13}]
+	// 1 [{0 13}]
+	// 2 [{3 16}]
+	// 3 [{2 16}]
+}
+
+func ExampleLabeledUndirected_TwoEdgeConnectedComponents() {
+	// 0---1---2===3   4---5
+	//  \ /
+	//   6
+	//
+	// edges labeled 10 + edge number
+	var g graph.LabeledUndirected
+	g.AddEdge(graph.Edge{0, 1}, 10)
+	g.AddEdge(graph.Edge{1, 6}, 11)
+	g.AddEdge(graph.Edge{6, 0}, 12)
+	g.AddEdge(graph.Edge{1, 2}, 13)
+	g.AddEdge(graph.Edge{2, 3}, 14)
+	g.AddEdge(graph.Edge{2, 3}, 15) // parallel edge
+	g.AddEdge(graph.Edge{4, 5}, 16)
+	fmt.Println(g.TwoEdgeConnectedComponents())
+	// Output:
+	// [1 1 2 2 3 4 1] 4
+}
+
 /* shelved
 func ExampleBiconnectedComponents_Find() {
 	g := graph.AdjacencyList{
@@ -717,3 +857,93 @@ func ExampleBiconnectedComponents_Find() {
 	// Leaves: [4 11 9]
 }
 */
+
+func TestBridges(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	for i := 0; i < 100; i++ {
+		g := graph.GnmUndirected(15, 18, r)
+		// some parallel edges
+		for j := 0; j < 3; j++ {
+			n1 := graph.NI(r.Intn(15))
+			if to := g.AdjacencyList[n1]; len(to) > 0 {
+				g.AddEdge(n1, to[r.Intn(len(to))])
+			}
+		}
+		_, nc := g.ConnectedComponentInts()
+		isBridge := map[graph.Edge]bool{}
+		g.Bridges(func(e graph.Edge) bool {
+			isBridge[e] = true
+			return true
+		})
+		// brute force: an edge is a bridge if removing it increases the
+		// number of connected components.
+		nb := 0
+		g.Edges(func(e graph.Edge) {
+			h, _ := g.Copy()
+			h.RemoveEdge(e.N1, e.N2)
+			_, hc := h.ConnectedComponentInts()
+			want := hc > nc
+			got := isBridge[e] || isBridge[graph.Edge{e.N2, e.N1}]
+			if got != want {
+				t.Fatalf("edge %v bridge = %t, want %t", e, got, want)
+			}
+			if want {
+				nb++
+			}
+		})
+		if nb != len(isBridge) {
+			t.Fatalf("%d bridges, want %d", len(isBridge), nb)
+		}
+		// components of bridge tree match connected components
+		tr, ci := g.BridgeTree()
+		if _, tc := tr.ConnectedComponentInts(); tc != nc {
+			t.Fatalf("bridge tree has %d components, want %d", tc, nc)
+		}
+		if tr.Order() != len(isBridge)+nc {
+			t.Fatal("bridge tree order", tr.Order(), "ci", ci)
+		}
+	}
+}
+
+func TestMaximumClique(t *testing.T) {
+	r := rand.New(rand.NewSource(59))
+	for i := 0; i < 20; i++ {
+		g, _ := graph.GnpUndirected(30, .5, r)
+		max := 0
+		g.BronKerbosch3(g.BKPivotMaxDegree, func(c bits.Bits) bool {
+			if n := c.OnesCount(); n > max {
+				max = n
+			}
+			return true
+		})
+		c, exact := g.MaximumClique(context.Background(), 0)
+		if !exact {
+			t.Fatal("search not exact")
+		}
+		if n := c.OnesCount(); n != max {
+			t.Fatalf("clique size %d, want %d", n, max)
+		}
+		// verify clique
+		c.IterateOnes(func(n1 int) bool {
+			c.IterateOnes(func(n2 int) bool {
+				if n1 != n2 {
+					if has, _ := g.HasArc(graph.NI(n1), graph.NI(n2)); !has {
+						t.Fatal(n1, n2, "not adjacent")
+					}
+				}
+				return true
+			})
+			return true
+		})
+	}
+	// budget stops search early
+	g, _ := graph.GnpUndirected(200, .5, r)
+	if _, exact := g.MaximumClique(context.Background(), 10); exact {
+		t.Fatal("budget not honored")
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, exact := g.MaximumClique(ctx, 0); exact {
+		t.Fatal("cancellation not honored")
+	}
+}
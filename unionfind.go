@@ -0,0 +1,169 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph
+
+// unionfind.go has a union-find data structure and incremental
+// connectivity built on it.
+
+// UnionFind represents a partition of nodes into disjoint sets.
+//
+// It is the classic union-find or disjoint-set data structure, with
+// union by rank and path compression.  Operations take nearly constant
+// amortized time.
+//
+// Nodes are numbered 0 through n-1 for some n, as for graphs.  Each set
+// is identified by a representative node.  The zero value is an empty
+// UnionFind, with no nodes.  Use NewUnionFind or Grow to add nodes.
+type UnionFind struct {
+	set   []ufElement
+	count int
+}
+
+type ufElement struct {
+	from NI // -1 for representatives
+	rank int
+	size int // valid for representatives
+}
+
+// NewUnionFind creates a UnionFind of n nodes, each in its own set.
+func NewUnionFind(n int) *UnionFind {
+	u := &UnionFind{}
+	u.Grow(n)
+	return u
+}
+
+// Connected returns true if x and y are in the same set.
+func (u *UnionFind) Connected(x, y NI) bool {
+	return u.Find(x) == u.Find(y)
+}
+
+// Count returns the number of disjoint sets.
+func (u *UnionFind) Count() int {
+	return u.count
+}
+
+// Find returns the representative node of the set containing n.
+func (u *UnionFind) Find(n NI) NI {
+	// fast paths for n == root or from root.
+	// no updates need in these cases.
+	s := u.set
+	fr := s[n].from
+	if fr < 0 { // n is root
+		return n
+	}
+	n, fr = fr, s[fr].from
+	if fr < 0 { // n is from root
+		return n
+	}
+	// otherwise updates needed.
+	// two iterative passes (rather than recursion or stack)
+	// pass 1: find root
+	r := fr
+	for {
+		f := s[r].from
+		if f < 0 {
+			break
+		}
+		r = f
+	}
+	// pass 2: update froms
+	for {
+		s[n].from = r
+		if fr == r {
+			return r
+		}
+		n = fr
+		fr = s[n].from
+	}
+}
+
+// Grow adds nodes as needed so that u has at least n nodes.
+//
+// Each added node is in a new set by itself.
+func (u *UnionFind) Grow(n int) {
+	for len(u.set) < n {
+		u.set = append(u.set, ufElement{from: -1, size: 1})
+		u.count++
+	}
+}
+
+// Order returns the number of nodes in u.
+func (u *UnionFind) Order() int {
+	return len(u.set)
+}
+
+// Size returns the number of nodes in the set containing n.
+func (u *UnionFind) Size(n NI) int {
+	return u.set[u.Find(n)].size
+}
+
+// Union merges the sets containing x and y.
+//
+// It returns true if disjoint sets were merged, false if x and y were
+// already in the same set.
+func (u *UnionFind) Union(x, y NI) bool {
+	xr := u.Find(x)
+	yr := u.Find(y)
+	if xr == yr {
+		return false
+	}
+	u.count--
+	switch xe, ye := &u.set[xr], &u.set[yr]; {
+	case xe.rank < ye.rank:
+		xe.from = yr
+		ye.size += xe.size
+	case xe.rank == ye.rank:
+		xe.rank++
+		fallthrough
+	default:
+		ye.from = xr
+		xe.size += ye.size
+	}
+	return true
+}
+
+// IncrementalUndirected is an undirected graph that maintains its
+// connected components as edges are added.
+//
+// Add edges with the AddEdge method of IncrementalUndirected.  Components
+// are then available from the Components member without traversing the
+// graph.  Adding edges by other means, or removing edges, will leave
+// Components invalid.
+type IncrementalUndirected struct {
+	Undirected
+	Components UnionFind
+}
+
+// NewIncrementalUndirected creates an IncrementalUndirected from an
+// existing undirected graph.
+//
+// The graph g is not copied.  Its adjacency list becomes the adjacency list
+// of the result.
+func NewIncrementalUndirected(g Undirected) *IncrementalUndirected {
+	p := &IncrementalUndirected{Undirected: g}
+	p.Components.Grow(len(g.AdjacencyList))
+	for fr, to := range g.AdjacencyList {
+		for _, to := range to {
+			p.Components.Union(NI(fr), to)
+		}
+	}
+	return p
+}
+
+// AddEdge adds an edge to the graph and updates connected components.
+//
+// The edge is added with Undirected.AddEdge, expanding the graph as needed.
+//
+// Returned is true if the edge joined two previously disconnected
+// components.
+func (p *IncrementalUndirected) AddEdge(n1, n2 NI) (joined bool) {
+	p.Undirected.AddEdge(n1, n2)
+	p.Components.Grow(len(p.AdjacencyList))
+	return p.Components.Union(n1, n2)
+}
+
+// Connected returns true if there is a path between n1 and n2.
+func (p *IncrementalUndirected) Connected(n1, n2 NI) bool {
+	return p.Components.Connected(n1, n2)
+}
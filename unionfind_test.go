@@ -0,0 +1,84 @@
+// Copyright 2014 Sonia Keys
+// License MIT: http://opensource.org/licenses/MIT
+
+package graph_test
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/soniakeys/graph"
+)
+
+func ExampleUnionFind() {
+	u := graph.NewUnionFind(6)
+	u.Union(0, 1)
+	u.Union(1, 2)
+	u.Union(3, 4)
+	fmt.Println(u.Count(), "sets")
+	fmt.Println(u.Connected(0, 2), u.Connected(2, 3))
+	fmt.Println(u.Size(2), u.Size(3), u.Size(5))
+	fmt.Println(u.Union(2, 0))
+	// Output:
+	// 3 sets
+	// true false
+	// 3 2 1
+	// false
+}
+
+func ExampleIncrementalUndirected() {
+	var g graph.IncrementalUndirected
+	g.AddEdge(0, 1)
+	g.AddEdge(2, 3)
+	fmt.Println(g.Components.Count(), g.Connected(0, 3))
+	fmt.Println(g.AddEdge(1, 2))
+	fmt.Println(g.Components.Count(), g.Connected(0, 3))
+	fmt.Println(g.AddEdge(3, 0))
+	// Output:
+	// 2 false
+	// true
+	// 1 true
+	// false
+}
+
+func ExampleNewIncrementalUndirected() {
+	// 0--1  2  3--4
+	var u graph.Undirected
+	u.AddEdge(0, 1)
+	u.AddEdge(3, 4)
+	u.AdjacencyList[2] = nil
+	g := graph.NewIncrementalUndirected(u)
+	fmt.Println(g.Components.Count())
+	g.AddEdge(2, 5)
+	fmt.Println(g.Components.Count(), g.Components.Size(5))
+	// Output:
+	// 3
+	// 3 2
+}
+
+func TestIncrementalUndirected(t *testing.T) {
+	r := rand.New(rand.NewSource(3))
+	var g graph.IncrementalUndirected
+	for i := 0; i < 300; i++ {
+		g.AddEdge(graph.NI(r.Intn(200)), graph.NI(r.Intn(200)))
+		ci, nc := g.ConnectedComponentInts()
+		if g.Components.Count() != nc {
+			t.Fatalf("Count = %d, want %d", g.Components.Count(), nc)
+		}
+		size := make([]int, nc+1)
+		for _, c := range ci {
+			size[c]++
+		}
+		for n := 0; n < 10; n++ {
+			n1 := graph.NI(r.Intn(len(ci)))
+			n2 := graph.NI(r.Intn(len(ci)))
+			if g.Connected(n1, n2) != (ci[n1] == ci[n2]) {
+				t.Fatal("Connected wrong")
+			}
+			if g.Components.Size(n1) != size[ci[n1]] {
+				t.Fatal("Size wrong")
+			}
+		}
+	}
+}